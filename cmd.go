@@ -5,7 +5,9 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"net"
 	"os"
 	"strconv"
 	"time"
@@ -13,6 +15,7 @@ import (
 	"log/slog"
 
 	"github.com/nhelke/goupnpc/goupnp"
+	"github.com/nhelke/goupnpc/goupnp/ssdp"
 )
 
 func main() {
@@ -21,12 +24,43 @@ func main() {
 
 	if len(os.Args) < 2 {
 		printUsage()
+	} else if os.Args[1] == "m" {
+		st := ssdp.All
+		if len(os.Args) > 2 {
+			st = os.Args[2]
+		}
+		responses, _ := ssdp.Search(st, nil, &net.UDPAddr{IP: net.IPv4zero},
+			3*time.Second)
+		for _, resp := range responses {
+			fmt.Println(resp.Source, resp)
+		}
 	} else {
 		discover := goupnp.DiscoverIGD()
 		if os.Args[1] == "s" {
 			igd := <-discover
 			status := <-igd.GetConnectionStatus()
 			fmt.Printf("%+v\n", status)
+		} else if os.Args[1] == "i" {
+			igd := <-discover
+			description := igd.Description()
+			fmt.Println(&description.Device)
+			description.Device.Walk(func(device *goupnp.Device) bool {
+				for i, service := range device.Services {
+					// The connection service the IGD is bound to is starred
+					marker := " "
+					if &device.Services[i] == igd.ConnectionService() {
+						marker = "*"
+					}
+					controlURL, err := description.ResolveURL(
+						service.ControlURL, igd.DescriptionURL())
+					if err != nil {
+						fmt.Println("  ", marker, service.ServiceType, err)
+					} else {
+						fmt.Println("  ", marker, service.ServiceType, controlURL)
+					}
+				}
+				return true
+			})
 		} else if os.Args[1] == "l" {
 			igd := <-discover
 			for portMapping := range igd.ListRedirections() {
@@ -38,6 +72,61 @@ func main() {
 			proto := goupnp.ParseProtocol(os.Args[3])
 			myMapping := <-igd.AddLocalPortRedirection(uint16(port), proto)
 			fmt.Printf("%+v\n", myMapping)
+		} else if os.Args[1] == "d" {
+			igd := <-discover
+			port, _ := strconv.Atoi(os.Args[2])
+			proto := goupnp.ParseProtocol(os.Args[3])
+			err := <-igd.DeletePortRedirection(&goupnp.PortMapping{
+				ExternalPort: uint16(port),
+				Protocol:     proto,
+			})
+			fmt.Println(err)
+		} else if os.Args[1] == "e" {
+			igd := <-discover
+			subscription, _ := igd.Subscribe(context.Background(),
+				igd.ConnectionService())
+			for event := range subscription.Events {
+				fmt.Println(event.Seq, event.Variables)
+			}
+		} else if os.Args[1] == "r" {
+			igd := <-discover
+			fmt.Println(igd.ForceTermination(context.Background()))
+			fmt.Println(igd.RequestConnection(context.Background()))
+		} else if os.Args[1] == "t" {
+			igd := <-discover
+			properties, _ := igd.GetCommonLinkProperties(context.Background())
+			fmt.Printf("%+v\n", properties)
+			if info, err := igd.GetLinkInfo(context.Background()); err == nil {
+				fmt.Println(info)
+			}
+			sampler := igd.NewTrafficSampler()
+			for {
+				rates, err := sampler.Sample(context.Background())
+				if err != nil {
+					fmt.Println(err)
+					break
+				} else if rates != nil {
+					fmt.Println(rates)
+				}
+				time.Sleep(time.Second)
+			}
+		} else if os.Args[1] == "n" {
+			igd := <-discover
+			config, err := igd.GetLANHostConfig(context.Background())
+			if err != nil {
+				fmt.Println(err)
+			} else {
+				fmt.Printf("%+v\n", config)
+			}
+		} else if os.Args[1] == "q" {
+			igd := <-discover
+			value, err := igd.QueryStateVariable(context.Background(),
+				igd.ConnectionService(), os.Args[2])
+			if err != nil {
+				fmt.Println(err)
+			} else {
+				fmt.Println(value)
+			}
 		} else {
 			printUsage()
 		}
@@ -50,11 +139,29 @@ func printUsage() {
 	fmt.Println(
 		`Usage: goupnpc s
            Print IGD Status
+       goupnpc i
+           Print the IGD model and the services it provides
        goupnpc a port protocol
            Add local port mapping with internal and external ports equal to
            port and protocol equal to, well I will let you guess
+       goupnpc d port protocol
+           Delete the port mapping with external port and protocol as passed
        goupnpc l
            Lists all port mappings on the IGD
+       goupnpc e
+           Print the events of the connection service of the IGD as they come
+       goupnpc r
+           Bounce the WAN connection of the IGD
+       goupnpc t
+           Print the WAN link properties and status then its throughput
+           every second
+       goupnpc n
+           Print the LAN configuration of the IGD, e.g. its DHCP range
+       goupnpc q variable
+           Print the value of a state variable of the connection service of
+           the IGD, e.g. PortMappingNumberOfEntries
+       goupnpc m [search target]
+           Lists all devices answering an SSDP search, by default ssdp:all
 NOTA BENE No error checking is performed, if anything goes wrong, it will
 probably panic on nil or something`)
 }
@@ -1,3 +1,7 @@
 module github.com/nhelke/goupnpc
 
-go 1.26
+go 1.26.0
+
+require golang.org/x/net v0.60.0
+
+require golang.org/x/sys v0.48.0 // indirect
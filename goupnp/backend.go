@@ -2,18 +2,31 @@ package goupnp
 
 import (
 	"bytes"
+	"context"
 	"encoding/xml"
 	"fmt"
 	"io"
-
-	"net"
 	"net/http"
+	"net/url"
+	"strings"
 
 	"log/slog"
 )
 
 type protocol int
 
+// This method returns the IANA protocol number of the protocol, as used by
+// WANIPv6FirewallControl rather than its name.
+func (self protocol) number() uint16 {
+	switch self {
+	case TCP:
+		return 6
+	case UDP:
+		return 17
+	}
+	return 0
+}
+
 func (self protocol) String() (str string) {
 	switch self {
 	case TCP:
@@ -26,96 +39,35 @@ func (self protocol) String() (str string) {
 	return
 }
 
-type deviceElement struct {
-	FriendlyName string `xml:"friendlyName"`
-	Manufacturer string `xml:"manufacturer"`
-
-	Services []struct {
-		ServiceType string `xml:"serviceType"`
-		ControlURL  string `xml:"controlURL"`
-	} `xml:"serviceList>service"`
-
-	Devices []deviceElement `xml:"deviceList>device,omitempty"`
-}
-
-type deviceDescription struct {
-	XMLName xml.Name `xml:"urn:schemas-upnp-org:device-1-0 root"`
-
-	SpecVersion struct {
-		Major int `xml:"major"`
-		Minor int `xml:"minor"`
-	} `xml:"specVersion"`
-
-	URLBase string
-
-	Device deviceElement `xml:"device"`
-}
-
 const (
 	connectionTypeStringWANIP  = "urn:schemas-upnp-org:service:WANIPConnection:1"
 	connectionTypeStringWANPPP = "urn:schemas-upnp-org:service:WANPPPConnection:1"
+	firewallTypeString         = "urn:schemas-upnp-org:service:WANIPv6FirewallControl:1"
 )
 
-func statusRequestStringReader(upnptype string) io.Reader {
-	return bytes.NewReader(fmt.Appendf(nil, statusRequestString, upnptype))
+type getGenericPortMappingEntryRequest struct {
+	NewPortMappingIndex uint
 }
 
-const statusRequestString = `<?xml version="1.0"?>
-<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/"
-s:encodingStyle="http://schemas.xmlsoap.org/soap/encoding/">
-<s:Body>
-<u:GetStatusInfo xmlns:u="%s">
-</u:GetStatusInfo>
-</s:Body>
-</s:Envelope>
-`
-
-const externalIPRequestString = `<?xml version="1.0"?>
-<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/"
-s:encodingStyle="http://schemas.xmlsoap.org/soap/encoding/">
-<s:Body>
-<u:GetExternalIPAddress xmlns:u="%s"></u:GetExternalIPAddress>
-</s:Body>
-</s:Envelope>
-`
-
-func externalIPRequestStringReader(upnptype string) io.Reader {
-	return bytes.NewReader(fmt.Appendf(nil, externalIPRequestString, upnptype))
+type addPortMappingRequest struct {
+	NewRemoteHost             string
+	NewExternalPort           uint16
+	NewProtocol               string
+	NewInternalPort           uint16
+	NewInternalClient         string
+	NewEnabled                soapBool
+	NewPortMappingDescription string
+	NewLeaseDuration          uint
 }
 
-const portMappingRequestString = `<?xml version="1.0"?>
-<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/" ` +
-	`s:encodingStyle="http://schemas.xmlsoap.org/soap/encoding/"><s:Body>` +
-	`<u:GetGenericPortMappingEntry xmlns:u="%s"><NewPortMappingIndex>%d` +
-	`</NewPortMappingIndex></u:GetGenericPortMappingEntry></s:Body></s:Envelope>
-`
-
-func portMappingRequestStringReader(upnptype string, index uint) io.Reader {
-	str := fmt.Sprintf(portMappingRequestString, upnptype, index)
-	return bytes.NewReader([]byte(str))
-}
-
-const createPortMappingString = `<?xml version="1.0"?>
-<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/" ` +
-	`s:encodingStyle="http://schemas.xmlsoap.org/soap/encoding/"><s:Body>` +
-	`<u:AddPortMapping xmlns:u="%s"><NewRemoteHost></NewRemoteHost>` +
-	`<NewExternalPort>%d</NewExternalPort><NewProtocol>%s</NewProtocol>` +
-	`<NewInternalPort>%d</NewInternalPort>` +
-	`<NewInternalClient>%s</NewInternalClient><NewEnabled>1</NewEnabled>` +
-	`<NewPortMappingDescription>%s</NewPortMappingDescription>` +
-	`<NewLeaseDuration>0</NewLeaseDuration>` +
-	`</u:AddPortMapping></s:Body></s:Envelope>
-`
-
-func createPortMappingStringReader(upnptype string, port uint16,
-	proto protocol, localAddr net.IP, description string) io.Reader {
-	str := fmt.Sprintf(createPortMappingString, upnptype, port, proto, port,
-		localAddr, description)
-	return bytes.NewReader([]byte(str))
+type deletePortMappingRequest struct {
+	NewRemoteHost   string
+	NewExternalPort uint16
+	NewProtocol     string
 }
 
 type soapEnvelope struct {
-	XMLName xml.Name `xml:"http://schemas.xmlsoap.org/soap/envelope/ Envelope"`
+	XMLName xml.Name `xml:"Envelope"`
 
 	Body struct {
 		IP struct {
@@ -126,12 +78,51 @@ type soapEnvelope struct {
 		Status struct {
 			XMLName xml.Name `xml:"GetStatusInfoResponse"`
 
-			NewConnectionStatus string
+			NewConnectionStatus    string
+			NewLastConnectionError string
+			NewUptime              string
 		}
 		PortMapping soapPortMapping `xml:"GetGenericPortMappingEntryResponse"`
+
+		FirewallStatus struct {
+			XMLName xml.Name `xml:"GetFirewallStatusResponse"`
+
+			FirewallEnabled       string
+			InboundPinholeAllowed string
+		}
+		OutboundPinholeTimeout struct {
+			XMLName xml.Name `xml:"GetOutboundPinholeTimeoutResponse"`
+
+			OutboundPinholeTimeout uint
+		}
+		Pinhole struct {
+			XMLName xml.Name `xml:"AddPinholeResponse"`
+
+			UniqueID uint16
+		}
+		PinholePackets struct {
+			XMLName xml.Name `xml:"GetPinholePacketsResponse"`
+
+			PinholePackets uint
+		}
+		PinholeWorking struct {
+			XMLName xml.Name `xml:"CheckPinholeWorkingResponse"`
+
+			IsWorking string
+		}
 	}
 }
 
+// This function interprets the passed string as a UPnP boolean, which may be
+// any of "0", "1", "false", "true", "no" or "yes".
+func parseUPnPBool(str string) bool {
+	switch strings.ToLower(strings.TrimSpace(str)) {
+	case "1", "true", "yes":
+		return true
+	}
+	return false
+}
+
 type soapPortMapping struct {
 	Protocol       string `xml:"NewProtocol"`
 	ExternalPort   uint16 `xml:"NewExternalPort"`
@@ -142,43 +133,80 @@ type soapPortMapping struct {
 	Lease          uint   `xml:"NewLeaseDuration"`
 }
 
-func (self *IGD) soapRequest(requestType string,
-	requestXML io.Reader) (x *soapEnvelope, ok bool) {
-	req, err := http.NewRequest("POST", self.controlURL.String(), requestXML)
+// This method performs the passed request on the connection service of the
+// IGD, see marshalSOAP() for args.
+func (self *IGD) soapRequest(ctx context.Context, requestType string,
+	args any) (x *soapEnvelope, ok bool) {
+	self.mutex.Lock()
+	controlURL, upnptype := self.controlURL, self.upnptype
+	self.mutex.Unlock()
+	return self.soapRequestTo(ctx, controlURL, upnptype, requestType, args)
+}
+
+// This method performs the passed request on the service of the passed type
+// reachable at controlURL, which allows addressing services other than the
+// connection service of the IGD.
+func (self *IGD) soapRequestTo(ctx context.Context, controlURL *url.URL,
+	serviceType, requestType string, args any) (x *soapEnvelope, ok bool) {
+	envelope, err := marshalSOAP(serviceType, requestType, args)
+	if err != nil {
+		slog.Warn("While marshaling SOAP request", "error", err)
+		return
+	}
+	body, err := postSOAP(ctx, controlURL, serviceType, requestType,
+		bytes.NewReader(envelope))
+	if err != nil {
+		// IGDs answer with faults in the normal course of operations, e.g.
+		// when ListRedirections() reaches the end of the table
+		slog.Debug("While performing SOAP/HTTP request", "error", err)
+		return
+	}
+	err = decodeXML(body, &x, self.strict)
+	if err == nil {
+		err = checkNamespace(x.XMLName, soapEnvelopeNamespace, self.strict)
+	}
+	if err == nil {
+		ok = true
+	} else {
+		slog.Warn("While unmarshaling XML", "error", err)
+	}
+	return
+}
+
+// This function POSTs the passed SOAP envelope to controlURL and returns the
+// body of the response. SOAP faults are returned as *UPnPError.
+func postSOAP(ctx context.Context, controlURL *url.URL, serviceType,
+	requestType string, requestXML io.Reader) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", controlURL.String(),
+		requestXML)
 	if err != nil {
-		panic("Programming Error: This hand crafted http.Request should not be bad")
+		return nil, err
 	}
-	req.Header.Add("Content-Type", "text/xml")
+	req.Header.Add("Content-Type", `text/xml; charset="utf-8"`)
 	req.Header.Add("SOAPAction",
-		`"`+self.upnptype+"#"+requestType+`"`)
+		`"`+serviceType+"#"+requestType+`"`)
 	req.Header.Add("Connection", "Close")
 	req.Header.Add("Cache-Control", "no-cache")
 	req.Header.Add("Pragma", "no-cache")
 
 	resp, err := http.DefaultClient.Do(req)
-	if err == nil {
-		// We got something back, lets not leak it
-		defer resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	// We got something back, lets not leak it
+	defer resp.Body.Close()
 
-		if resp.StatusCode != http.StatusOK {
-			return
-		}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	slog.Debug("SOAP Response", "response", string(body))
 
-		body, err := io.ReadAll(resp.Body)
-		if err == nil {
-			slog.Debug("SOAP Response", "response", string(body))
-			err := xml.Unmarshal(body, &x)
-			if err == nil {
-				ok = true
-			} else {
-				slog.Warn("While unmarshaling XML", "error", err)
-			}
-		} else {
-			slog.Warn("While reading response", "error", err)
+	if resp.StatusCode != http.StatusOK {
+		if upnpErr := parseSOAPFault(body); upnpErr != nil {
+			return nil, upnpErr
 		}
-	} else {
-		slog.Warn("While performing SOAP/HTTP request", "error", err)
+		return nil, fmt.Errorf("%s: %s", requestType, resp.Status)
 	}
-
-	return
+	return body, nil
 }
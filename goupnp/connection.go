@@ -0,0 +1,212 @@
+package goupnp
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"log/slog"
+)
+
+// This type enumerates the values of the ConnectionStatus state variable of
+// WANIPConnection and WANPPPConnection services.
+type ConnectionState int
+
+const (
+	// Any value the specifications do not define
+	ConnectionUnknown ConnectionState = iota
+	ConnectionUnconfigured
+	ConnectionConnecting
+	// Only reported by WANPPPConnection
+	ConnectionAuthenticating
+	ConnectionConnected
+	ConnectionPendingDisconnect
+	ConnectionDisconnecting
+	ConnectionDisconnected
+)
+
+var connectionStateStrings = [...]string{
+	ConnectionUnknown:           "Unknown",
+	ConnectionUnconfigured:      "Unconfigured",
+	ConnectionConnecting:        "Connecting",
+	ConnectionAuthenticating:    "Authenticating",
+	ConnectionConnected:         "Connected",
+	ConnectionPendingDisconnect: "PendingDisconnect",
+	ConnectionDisconnecting:     "Disconnecting",
+	ConnectionDisconnected:      "Disconnected",
+}
+
+func (self ConnectionState) String() string {
+	if self < 0 || int(self) >= len(connectionStateStrings) {
+		return "#(Bad ConnectionState Value)"
+	}
+	return connectionStateStrings[self]
+}
+
+// This function returns the ConnectionState the passed value of the
+// ConnectionStatus state variable stands for, ignoring case, or
+// ConnectionUnknown.
+func ParseConnectionState(str string) ConnectionState {
+	str = strings.TrimSpace(str)
+	for state, name := range connectionStateStrings {
+		if state != int(ConnectionUnknown) && strings.EqualFold(str, name) {
+			return ConnectionState(state)
+		}
+	}
+	return ConnectionUnknown
+}
+
+// This method returns every WANIPConnection and WANPPPConnection service found
+// in the description of the IGD, in depth-first order. Routers with several
+// WANConnectionDevices, e.g. a DSL PPP connection alongside an unused IP
+// connection, list more than one.
+func (self *IGD) ConnectionServices() []*Service {
+	return self.description.Device.FindServices(connectionTypeStringWANIP,
+		connectionTypeStringWANPPP)
+}
+
+// This method returns the connection service the IGD is bound to, that is the
+// one GetConnectionStatus(), AddLocalPortRedirection() and the like act on.
+func (self *IGD) ConnectionService() *Service {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	return self.connection
+}
+
+// This method binds the IGD to the passed connection service, which must be
+// one of ConnectionServices(), overriding the choice made during discovery.
+func (self *IGD) SelectConnectionService(service *Service) error {
+	if !slices.Contains(self.ConnectionServices(), service) {
+		return errors.New("Not a connection service of this IGD")
+	}
+	controlURL, err := self.resolveURL(service.ControlURL)
+	if err != nil {
+		return err
+	}
+
+	self.mutex.Lock()
+	self.connection = service
+	self.controlURL = controlURL
+	self.upnptype = service.ServiceType
+	self.mutex.Unlock()
+	return nil
+}
+
+// How long discovery waits for the connection services of an IGD to report
+// their status, those failing to answer in time are taken not to be connected,
+// and, within that time, for its Layer3Forwarding service to report the
+// default one.
+const (
+	selectTimeout            = 2 * time.Second
+	defaultConnectionTimeout = time.Second
+)
+
+// This method binds the IGD to the default connection service its
+// Layer3Forwarding service reports, or else to the first of its connection
+// services which reports being Connected, or else to the first one. The IGD is
+// only queried, within ctx, if there is more than one connection service to
+// choose from.
+func (self *IGD) selectConnectedService(ctx context.Context) error {
+	services := self.ConnectionServices()
+	if len(services) == 0 {
+		return errors.New("Control URL not found")
+	}
+	if len(services) > 1 {
+		// The default connection service is asked for first, without
+		// letting it use up the time left to query the status of each
+		l3fCtx, cancel := context.WithTimeout(ctx, defaultConnectionTimeout)
+		service, err := self.GetDefaultConnectionService(l3fCtx)
+		cancel()
+		if err == nil {
+			return self.SelectConnectionService(service)
+		}
+		slog.Debug("No default connection service", "error", err)
+
+		states := make([]ConnectionState, len(services))
+		var wg sync.WaitGroup
+		for i, service := range services {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				states[i] = self.connectionServiceState(ctx, service)
+			}()
+		}
+		wg.Wait()
+		for i, service := range services {
+			slog.Debug("Connection service candidate", "service", service.ServiceID,
+				"state", states[i])
+			if states[i] == ConnectionConnected {
+				return self.SelectConnectionService(service)
+			}
+		}
+	}
+	return self.SelectConnectionService(services[0])
+}
+
+// This method returns the ConnectionState the passed connection service
+// reports, or ConnectionUnknown if it cannot be queried within ctx.
+func (self *IGD) connectionServiceState(ctx context.Context, service *Service) ConnectionState {
+	controlURL, err := self.resolveURL(service.ControlURL)
+	if err != nil {
+		return ConnectionUnknown
+	}
+	x, ok := self.soapRequestTo(ctx, controlURL, service.ServiceType,
+		"GetStatusInfo", struct{}{})
+	if !ok {
+		return ConnectionUnknown
+	}
+	return ParseConnectionState(x.Body.Status.NewConnectionStatus)
+}
+
+// This method returns the service of the passed type nearest to the
+// connection service the IGD is bound to, that is the first one found in the
+// smallest device subtree holding both, or nil if there is none. It notably
+// finds the WANCommonInterfaceConfig of the WANDevice carrying the connection
+// on routers with several WANDevices.
+func (self *IGD) serviceNearConnection(serviceType string) *Service {
+	path := devicePath(&self.description.Device, self.ConnectionService())
+	for i := len(path) - 1; i >= 0; i-- {
+		if _, service := path[i].FindService(serviceType); service != nil {
+			return service
+		}
+	}
+	return nil
+}
+
+// This method returns the service of the passed type provided by the very
+// device providing the connection service the IGD is bound to, that is its
+// WANConnectionDevice, or nil if there is none. Unlike serviceNearConnection()
+// it never returns a service of a sibling WANConnectionDevice, as the link
+// config services describe the link of their own device only.
+func (self *IGD) serviceOfConnectionDevice(serviceType string) *Service {
+	path := devicePath(&self.description.Device, self.ConnectionService())
+	if path == nil {
+		return nil
+	}
+	device := path[len(path)-1]
+	for i := range device.Services {
+		if device.Services[i].ServiceType == serviceType {
+			return &device.Services[i]
+		}
+	}
+	return nil
+}
+
+// This function returns the devices from root down to the one providing the
+// passed service, or nil if none does.
+func devicePath(root *Device, service *Service) []*Device {
+	for i := range root.Services {
+		if &root.Services[i] == service {
+			return []*Device{root}
+		}
+	}
+	for i := range root.Devices {
+		if path := devicePath(&root.Devices[i], service); path != nil {
+			return append([]*Device{root}, path...)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,207 @@
+package goupnp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// A DSL router whose first WANConnectionDevice holds an unused IP connection
+// and whose second the PPP connection actually in use.
+const exampleDSLDescription = `<?xml version="1.0"?>
+<root xmlns="urn:schemas-upnp-org:device-1-0">
+	<specVersion><major>1</major><minor>0</minor></specVersion>
+	<device>
+		<deviceType>urn:schemas-upnp-org:device:InternetGatewayDevice:1</deviceType>
+		<friendlyName>DSL Router</friendlyName>
+		<deviceList><device>
+			<deviceType>urn:schemas-upnp-org:device:WANDevice:1</deviceType>
+			<deviceList>
+				<device>
+					<deviceType>urn:schemas-upnp-org:device:WANConnectionDevice:1</deviceType>
+					<serviceList><service>
+						<serviceType>urn:schemas-upnp-org:service:WANIPConnection:1</serviceType>
+						<serviceId>urn:upnp-org:serviceId:WANIPConn1</serviceId>
+						<controlURL>/ctl/IPConn</controlURL>
+					</service></serviceList>
+				</device>
+				<device>
+					<deviceType>urn:schemas-upnp-org:device:WANConnectionDevice:1</deviceType>
+					<serviceList><service>
+						<serviceType>urn:schemas-upnp-org:service:WANPPPConnection:1</serviceType>
+						<serviceId>urn:upnp-org:serviceId:WANPPPConn1</serviceId>
+						<controlURL>/ctl/PPPConn</controlURL>
+					</service></serviceList>
+				</device>
+			</deviceList>
+		</device></deviceList>
+	</device>
+</root>
+`
+
+const statusResponseString = `<?xml version="1.0"?>
+<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/" ` +
+	`s:encodingStyle="http://schemas.xmlsoap.org/soap/encoding/"><s:Body>` +
+	`<u:GetStatusInfoResponse xmlns:u="%s">` +
+	`<NewConnectionStatus>%s</NewConnectionStatus>` +
+	`<NewLastConnectionError>ERROR_NONE</NewLastConnectionError>` +
+	`<NewUptime>0</NewUptime>` +
+	`</u:GetStatusInfoResponse></s:Body></s:Envelope>
+`
+
+func TestSelectConnectedService(t *testing.T) {
+	statuses := map[string]string{
+		"/ctl/IPConn":  "Disconnected",
+		"/ctl/PPPConn": "Connected",
+	}
+	mux := http.NewServeMux()
+	for path, status := range statuses {
+		mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+			serviceType := connectionTypeStringWANIP
+			if path == "/ctl/PPPConn" {
+				serviceType = connectionTypeStringWANPPP
+			}
+			fmt.Fprintf(w, statusResponseString, serviceType, status)
+		})
+	}
+
+	igd := newTestIGDWith(t, loopbackInterface, exampleDSLDescription, nil, mux)
+
+	services := igd.ConnectionServices()
+	if len(services) != 2 {
+		t.Fatalf("Expected 2 connection services, got %v", services)
+	}
+	if igd.ConnectionService() != services[1] ||
+		!strings.HasSuffix(igd.String(), "/ctl/PPPConn") {
+		t.Errorf("Bound to %v rather than the connected PPP service", igd)
+	}
+	if err := igd.SelectConnectionService(services[0]); err != nil {
+		t.Fatal(err)
+	}
+	if igd.ConnectionService() != services[0] ||
+		!strings.HasSuffix(igd.String(), "/ctl/IPConn") {
+		t.Errorf("Bound to %v rather than the selected IP service", igd)
+	}
+	status, ok := <-igd.GetConnectionStatus()
+	if !ok || status.Connected {
+		t.Errorf("Selected service status incorrectly reported as %v", status)
+	}
+
+	if err := igd.SelectConnectionService(&Service{ControlURL: "/ctl/Other"}); err == nil {
+		t.Error("Expected an error selecting a service of another IGD")
+	}
+}
+
+func TestSelectConnectedServiceTimeout(t *testing.T) {
+	// The unused IP connection accepts connections but never answers
+	stop := make(chan struct{})
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	defer close(stop)
+	mux.HandleFunc("/ctl/IPConn", func(w http.ResponseWriter, r *http.Request) {
+		<-stop
+	})
+	mux.HandleFunc("/ctl/PPPConn", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, statusResponseString, connectionTypeStringWANPPP, "Connected")
+	})
+
+	description, err := parseDeviceDescription([]byte(exampleDSLDescription), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	descURL, _ := url.Parse(server.URL + "/rootDesc.xml")
+	igd := &IGD{description: description, descURL: descURL}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := igd.selectConnectedService(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Selection took %v despite the deadline", elapsed)
+	}
+	if igd.ConnectionService() != igd.ConnectionServices()[1] {
+		t.Errorf("Bound to %v rather than the connected PPP service", igd)
+	}
+}
+
+func TestSelectConnectedServiceStalledIGD(t *testing.T) {
+	// The SCPDs and the Layer3Forwarding service never answer
+	stop := make(chan struct{})
+	defer close(stop)
+	soap := newSOAPTestHandler(t, func(action string, args map[string]string) map[string]string {
+		if action == "GetDefaultConnectionService" {
+			<-stop
+		}
+		return map[string]string{"NewConnectionStatus": "Connected"}
+	})
+	start := time.Now()
+	igd := newTestIGDWith(t, loopbackInterface, exampleForwardingDescription, nil,
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != "POST" {
+				<-stop
+				return
+			}
+			soap.ServeHTTP(w, r)
+		}))
+	if elapsed := time.Since(start); elapsed > selectTimeout+time.Second {
+		t.Errorf("Discovery took %v despite the deadline", elapsed)
+	}
+	if igd.ConnectionService() != igd.ConnectionServices()[0] {
+		t.Errorf("Bound to %v rather than the first connected service", igd)
+	}
+}
+
+func TestConnectionStateParsing(t *testing.T) {
+	states := map[string]ConnectionState{
+		"Connected":         ConnectionConnected,
+		" disconnected ":    ConnectionDisconnected,
+		"PendingDisconnect": ConnectionPendingDisconnect,
+		"Authenticating":    ConnectionAuthenticating,
+		"Unknown":           ConnectionUnknown,
+		"Bogus":             ConnectionUnknown,
+	}
+	for str, expected := range states {
+		if actual := ParseConnectionState(str); actual != expected {
+			t.Errorf("%q parsed as %v, expected %v", str, actual, expected)
+		}
+	}
+	if str := ConnectionState(42).String(); str != "#(Bad ConnectionState Value)" {
+		t.Errorf("Bad value formatted as %q", str)
+	}
+}
+
+func TestGetConnectionStatus(t *testing.T) {
+	connection := &testConnection{status: "Connecting"}
+	igd := newTestIGDWith(t, loopbackInterface, belkinDescription, nil, connection)
+
+	// States other than Connected and Disconnected used to be errors
+	status, ok := <-igd.GetConnectionStatus()
+	if !ok || status.State != ConnectionConnecting || status.Connected ||
+		status.IP != nil || status.LastError != "ERROR_NONE" {
+		t.Errorf("Status incorrectly reported as %+v", status)
+	}
+
+	igd = newTestIGDWith(t, loopbackInterface, belkinDescription, nil,
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if strings.HasSuffix(r.Header.Get("SOAPAction"), `#GetStatusInfo"`) {
+				w.Write([]byte(exampleBelkinSOAP))
+			} else {
+				fmt.Fprintf(w, externalIPResponseString, "203.0.113.7")
+			}
+		}))
+
+	status, ok = <-igd.GetConnectionStatus()
+	if !ok || status.State != ConnectionConnected || !status.Connected ||
+		status.IP.String() != "203.0.113.7" || status.LastError != "ERROR_NONE" ||
+		status.Uptime != 194979*time.Second {
+		t.Errorf("Status incorrectly reported as %+v", status)
+	}
+}
@@ -0,0 +1,186 @@
+package goupnp
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// This type describes the connection type of the connection service of an
+// IGD, e.g. IP_Routed or IP_Bridged for WANIPConnection and IP_Routed,
+// DHCP_Spoofed, PPPoE_Bridged, PPTP_Relay, L2TP_Relay or PPPoE_Relay for
+// WANPPPConnection.
+type ConnectionTypeInfo struct {
+	Type          string
+	PossibleTypes []string
+}
+
+// This type tells whether the connection service of an IGD actually performs
+// NAT, and whether it supports Realm-Specific IP.
+type NATRSIPStatus struct {
+	RSIPAvailable bool
+	NATEnabled    bool
+}
+
+// The following methods invoke actions common to the WANIPConnection and
+// WANPPPConnection services on the connection service the IGD is bound to,
+// see SelectConnectionService(). Failures reported by the IGD are returned
+// wrapping a *UPnPError, notably for optional actions it does not implement.
+
+// This method returns the generated client matching the type of the
+// connection service the IGD is bound to: exactly one of the returned clients
+// is non-nil.
+func (self *IGD) connectionClient() (*WANIPConnection1, *WANPPPConnection1) {
+	service := self.ConnectionService()
+	if service.ServiceType == connectionTypeStringWANPPP {
+		return nil, &WANPPPConnection1{igd: self, Service: service}
+	}
+	return &WANIPConnection1{igd: self, Service: service}, nil
+}
+
+// This method asks the IGD to establish its WAN connection, e.g. after
+// ForceTermination(). It returns once the IGD has accepted, the connection
+// may take longer to come up.
+func (self *IGD) RequestConnection(ctx context.Context) error {
+	if ip, ppp := self.connectionClient(); ppp != nil {
+		return ppp.RequestConnection(ctx)
+	} else {
+		return ip.RequestConnection(ctx)
+	}
+}
+
+// This method asks the IGD to tear down its WAN connection gracefully, after
+// WarnDisconnectDelay.
+func (self *IGD) RequestTermination(ctx context.Context) error {
+	if ip, ppp := self.connectionClient(); ppp != nil {
+		return ppp.RequestTermination(ctx)
+	} else {
+		return ip.RequestTermination(ctx)
+	}
+}
+
+// This method tears down the WAN connection of the IGD immediately. Use
+// RequestConnection() to bring it back up, which the IGD is not bound to do
+// on its own.
+func (self *IGD) ForceTermination(ctx context.Context) error {
+	if ip, ppp := self.connectionClient(); ppp != nil {
+		return ppp.ForceTermination(ctx)
+	} else {
+		return ip.ForceTermination(ctx)
+	}
+}
+
+// This method fetches the current and possible connection types of the IGD.
+func (self *IGD) GetConnectionTypeInfo(ctx context.Context) (*ConnectionTypeInfo, error) {
+	var current, possible string
+	if ip, ppp := self.connectionClient(); ppp != nil {
+		resp, err := ppp.GetConnectionTypeInfo(ctx)
+		if err != nil {
+			return nil, err
+		}
+		current, possible = resp.NewConnectionType, resp.NewPossibleConnectionTypes
+	} else {
+		resp, err := ip.GetConnectionTypeInfo(ctx)
+		if err != nil {
+			return nil, err
+		}
+		current, possible = resp.NewConnectionType, resp.NewPossibleConnectionTypes
+	}
+	info := ConnectionTypeInfo{Type: strings.TrimSpace(current)}
+	// The possible types are a comma-separated list
+	for _, field := range strings.Split(possible, ",") {
+		if field = strings.TrimSpace(field); field != "" {
+			info.PossibleTypes = append(info.PossibleTypes, field)
+		}
+	}
+	return &info, nil
+}
+
+// This method sets the connection type of the IGD, which must be one of the
+// PossibleTypes GetConnectionTypeInfo() returns.
+func (self *IGD) SetConnectionType(ctx context.Context, connectionType string) error {
+	if ip, ppp := self.connectionClient(); ppp != nil {
+		return ppp.SetConnectionType(ctx,
+			&WANPPPConnection1SetConnectionTypeRequest{NewConnectionType: connectionType})
+	} else {
+		return ip.SetConnectionType(ctx,
+			&WANIPConnection1SetConnectionTypeRequest{NewConnectionType: connectionType})
+	}
+}
+
+// This method fetches whether the IGD performs NAT.
+func (self *IGD) GetNATRSIPStatus(ctx context.Context) (*NATRSIPStatus, error) {
+	if ip, ppp := self.connectionClient(); ppp != nil {
+		resp, err := ppp.GetNATRSIPStatus(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return &NATRSIPStatus{RSIPAvailable: resp.NewRSIPAvailable, NATEnabled: resp.NewNATEnabled}, nil
+	} else {
+		resp, err := ip.GetNATRSIPStatus(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return &NATRSIPStatus{RSIPAvailable: resp.NewRSIPAvailable, NATEnabled: resp.NewNATEnabled}, nil
+	}
+}
+
+// This method fetches after how long the IGD tears down its WAN connection
+// once established, zero meaning never.
+func (self *IGD) GetAutoDisconnectTime(ctx context.Context) (time.Duration, error) {
+	var seconds uint32
+	if ip, ppp := self.connectionClient(); ppp != nil {
+		resp, err := ppp.GetAutoDisconnectTime(ctx)
+		if err != nil {
+			return 0, err
+		}
+		seconds = resp.NewAutoDisconnectTime
+	} else {
+		resp, err := ip.GetAutoDisconnectTime(ctx)
+		if err != nil {
+			return 0, err
+		}
+		seconds = resp.NewAutoDisconnectTime
+	}
+	return time.Duration(seconds) * time.Second, nil
+}
+
+// This method fetches after how long without traffic the IGD tears down its
+// WAN connection, zero meaning never.
+func (self *IGD) GetIdleDisconnectTime(ctx context.Context) (time.Duration, error) {
+	var seconds uint32
+	if ip, ppp := self.connectionClient(); ppp != nil {
+		resp, err := ppp.GetIdleDisconnectTime(ctx)
+		if err != nil {
+			return 0, err
+		}
+		seconds = resp.NewIdleDisconnectTime
+	} else {
+		resp, err := ip.GetIdleDisconnectTime(ctx)
+		if err != nil {
+			return 0, err
+		}
+		seconds = resp.NewIdleDisconnectTime
+	}
+	return time.Duration(seconds) * time.Second, nil
+}
+
+// This method fetches how long the IGD warns LAN clients for before tearing
+// down its WAN connection.
+func (self *IGD) GetWarnDisconnectDelay(ctx context.Context) (time.Duration, error) {
+	var seconds uint32
+	if ip, ppp := self.connectionClient(); ppp != nil {
+		resp, err := ppp.GetWarnDisconnectDelay(ctx)
+		if err != nil {
+			return 0, err
+		}
+		seconds = resp.NewWarnDisconnectDelay
+	} else {
+		resp, err := ip.GetWarnDisconnectDelay(ctx)
+		if err != nil {
+			return 0, err
+		}
+		seconds = resp.NewWarnDisconnectDelay
+	}
+	return time.Duration(seconds) * time.Second, nil
+}
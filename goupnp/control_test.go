@@ -0,0 +1,103 @@
+package goupnp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConnectionControl(t *testing.T) {
+	var invoked []string
+	igd := newSOAPTestIGD(t, func(action string, args map[string]string) map[string]string {
+		invoked = append(invoked, action)
+		switch action {
+		case "RequestConnection", "RequestTermination", "ForceTermination":
+			return map[string]string{}
+		case "SetConnectionType":
+			if args["NewConnectionType"] != "IP_Bridged" {
+				return nil
+			}
+			return map[string]string{}
+		case "GetConnectionTypeInfo":
+			return map[string]string{
+				"NewConnectionType":          "IP_Routed",
+				"NewPossibleConnectionTypes": "IP_Routed, IP_Bridged,",
+			}
+		case "GetNATRSIPStatus":
+			return map[string]string{"NewRSIPAvailable": "0", "NewNATEnabled": "1"}
+		case "GetIdleDisconnectTime":
+			return map[string]string{"NewIdleDisconnectTime": "300"}
+		}
+		return nil
+	})
+	ctx := context.Background()
+
+	if err := igd.ForceTermination(ctx); err != nil {
+		t.Error(err)
+	}
+	if err := igd.RequestConnection(ctx); err != nil {
+		t.Error(err)
+	}
+	if err := igd.RequestTermination(ctx); err != nil {
+		t.Error(err)
+	}
+	if !slices.Equal(invoked, []string{"ForceTermination", "RequestConnection", "RequestTermination"}) {
+		t.Errorf("Invoked %v", invoked)
+	}
+
+	info, err := igd.GetConnectionTypeInfo(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Type != "IP_Routed" || !slices.Equal(info.PossibleTypes, []string{"IP_Routed", "IP_Bridged"}) {
+		t.Errorf("Connection type incorrectly parsed as %+v", info)
+	}
+	if err := igd.SetConnectionType(ctx, "IP_Bridged"); err != nil {
+		t.Error(err)
+	}
+
+	status, err := igd.GetNATRSIPStatus(ctx)
+	if err != nil || *status != (NATRSIPStatus{RSIPAvailable: false, NATEnabled: true}) {
+		t.Errorf("NAT status incorrectly parsed as %+v, %v", status, err)
+	}
+
+	if idle, err := igd.GetIdleDisconnectTime(ctx); idle != 5*time.Minute || err != nil {
+		t.Errorf("Idle disconnect time incorrectly parsed as %v, %v", idle, err)
+	}
+
+	// GetWarnDisconnectDelay is not implemented by this IGD
+	_, err = igd.GetWarnDisconnectDelay(ctx)
+	var upnpErr *UPnPError
+	if !errors.As(err, &upnpErr) {
+		t.Errorf("Expected an UPnPError, got %v", err)
+	}
+}
+
+func TestConnectionControlPPP(t *testing.T) {
+	var serviceTypes []string
+	soap := newSOAPTestHandler(t, func(action string, args map[string]string) map[string]string {
+		if action == "GetAutoDisconnectTime" {
+			return map[string]string{"NewAutoDisconnectTime": "3600"}
+		}
+		return nil
+	})
+	description := strings.ReplaceAll(belkinDescription, "WANIPConnection:1", "WANPPPConnection:1")
+	igd := newTestIGDWith(t, loopbackInterface, description, nil,
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			serviceType, _, _ := strings.Cut(strings.Trim(r.Header.Get("SOAPAction"), `"`), "#")
+			serviceTypes = append(serviceTypes, serviceType)
+			soap.ServeHTTP(w, r)
+		}))
+	serviceTypes = nil
+
+	if auto, err := igd.GetAutoDisconnectTime(context.Background()); auto != time.Hour || err != nil {
+		t.Errorf("Auto disconnect time incorrectly parsed as %v, %v", auto, err)
+	}
+	if !slices.Equal(serviceTypes, []string{connectionTypeStringWANPPP}) {
+		t.Errorf("Invoked on %v, expected the WANPPPConnection service", serviceTypes)
+	}
+}
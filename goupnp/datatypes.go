@@ -0,0 +1,84 @@
+package goupnp
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// These functions convert between Go values and the string representation of
+// the UPnP data types used in SOAP arguments, see section 2.5 of the UPnP
+// Device Architecture. They are mostly used by generated clients.
+
+func formatUint(v uint64) string {
+	return strconv.FormatUint(v, 10)
+}
+
+func formatInt(v int64) string {
+	return strconv.FormatInt(v, 10)
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// UPnP booleans are sent as "1" and "0", which every IGD understands unlike
+// "true" and "false".
+func formatBool(v bool) string {
+	if v {
+		return "1"
+	}
+	return "0"
+}
+
+// Times are sent in the ISO 8601 format of the dateTime data type, without a
+// time zone.
+func formatDateTime(v time.Time) string {
+	return v.Format("2006-01-02T15:04:05")
+}
+
+func parseUint[T ~uint8 | ~uint16 | ~uint32 | ~uint64](str string, bits int) (T, error) {
+	v, err := strconv.ParseUint(strings.TrimSpace(str), 10, bits)
+	return T(v), err
+}
+
+func parseInt[T ~int8 | ~int16 | ~int32 | ~int64](str string, bits int) (T, error) {
+	v, err := strconv.ParseInt(strings.TrimSpace(str), 10, bits)
+	return T(v), err
+}
+
+func parseFloat[T ~float32 | ~float64](str string, bits int) (T, error) {
+	v, err := strconv.ParseFloat(strings.TrimSpace(str), bits)
+	return T(v), err
+}
+
+// This function parses the passed string as a UPnP boolean, unlike
+// parseUPnPBool it fails on values other than those allowed by the standard.
+func parseBool(str string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(str)) {
+	case "1", "true", "yes":
+		return true, nil
+	case "0", "false", "no":
+		return false, nil
+	}
+	return false, fmt.Errorf("Invalid boolean %q", str)
+}
+
+// This function parses the passed string as any of the date, dateTime,
+// dateTime.tz, time or time.tz UPnP data types.
+func parseDateTime(str string) (time.Time, error) {
+	str = strings.TrimSpace(str)
+	for _, layout := range []string{
+		"2006-01-02T15:04:05Z07:00",
+		"2006-01-02T15:04:05",
+		"2006-01-02",
+		"15:04:05Z07:00",
+		"15:04:05",
+	} {
+		if t, err := time.Parse(layout, str); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("Invalid date or time %q", str)
+}
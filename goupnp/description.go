@@ -0,0 +1,216 @@
+package goupnp
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// This type models the root device description document every UPnP device
+// serves at the LOCATION it advertises over SSDP.
+//
+// URLs are kept exactly as found in the document, relative URLs are relative
+// to URLBase or, in its absence, to the URL the description was fetched from,
+// see ResolveURL().
+type DeviceDescription struct {
+	XMLName xml.Name `xml:"root"`
+
+	SpecVersion SpecVersion `xml:"specVersion"`
+
+	URLBase string `xml:"URLBase"`
+
+	Device Device `xml:"device"`
+}
+
+type SpecVersion struct {
+	Major int `xml:"major"`
+	Minor int `xml:"minor"`
+}
+
+// This type describes a UPnP device, the root device of a description as well
+// as each of its embedded devices.
+type Device struct {
+	DeviceType       string `xml:"deviceType"`
+	FriendlyName     string `xml:"friendlyName"`
+	Manufacturer     string `xml:"manufacturer"`
+	ManufacturerURL  string `xml:"manufacturerURL"`
+	ModelDescription string `xml:"modelDescription"`
+	ModelName        string `xml:"modelName"`
+	ModelNumber      string `xml:"modelNumber"`
+	ModelURL         string `xml:"modelURL"`
+	SerialNumber     string `xml:"serialNumber"`
+	UDN              string `xml:"UDN"`
+	UPC              string `xml:"UPC"`
+	PresentationURL  string `xml:"presentationURL"`
+
+	Icons    []Icon    `xml:"iconList>icon"`
+	Services []Service `xml:"serviceList>service"`
+	Devices  []Device  `xml:"deviceList>device"`
+}
+
+type Icon struct {
+	Mimetype string `xml:"mimetype"`
+	Width    int    `xml:"width"`
+	Height   int    `xml:"height"`
+	Depth    int    `xml:"depth"`
+	URL      string `xml:"url"`
+}
+
+// This type describes a service provided by a Device, that is where to
+// control it, subscribe to its events and fetch its description (SCPD).
+type Service struct {
+	ServiceType string `xml:"serviceType"`
+	ServiceID   string `xml:"serviceId"`
+	SCPDURL     string `xml:"SCPDURL"`
+	ControlURL  string `xml:"controlURL"`
+	EventSubURL string `xml:"eventSubURL"`
+}
+
+func (self *Device) String() string {
+	return self.FriendlyName + " (" + self.Manufacturer + " " +
+		self.ModelName + " " + self.ModelNumber + ")"
+}
+
+const deviceNamespace = "urn:schemas-upnp-org:device-1-0"
+
+// This function parses the passed root device description document,
+// tolerating the deviations from the specification commonly found on IGDs
+// such as a missing namespace or an ISO-8859-1 encoding.
+func ParseDeviceDescription(body []byte) (*DeviceDescription, error) {
+	return parseDeviceDescription(body, false)
+}
+
+func parseDeviceDescription(body []byte, strict bool) (*DeviceDescription, error) {
+	var x DeviceDescription
+	if err := decodeXML(body, &x, strict); err != nil {
+		return nil, err
+	}
+	if err := checkNamespace(x.XMLName, deviceNamespace, strict); err != nil {
+		return nil, err
+	}
+	return &x, nil
+}
+
+// This method calls visit with the device and each of its embedded devices,
+// depth-first, until visit returns false.
+func (self *Device) Walk(visit func(*Device) bool) bool {
+	if !visit(self) {
+		return false
+	}
+	for i := range self.Devices {
+		if !self.Devices[i].Walk(visit) {
+			return false
+		}
+	}
+	return true
+}
+
+// This method returns the first service found depth-first in the device tree
+// whose type is one of serviceTypes, along with the device providing it, or
+// nil if there is none.
+func (self *Device) FindService(serviceTypes ...string) (device *Device, service *Service) {
+	self.Walk(func(d *Device) bool {
+		for i := range d.Services {
+			if slices.Contains(serviceTypes, d.Services[i].ServiceType) {
+				device, service = d, &d.Services[i]
+				return false
+			}
+		}
+		return true
+	})
+	return
+}
+
+// This method returns every service in the device tree whose type is one of
+// serviceTypes, in depth-first order. Every service is returned if no type is
+// passed.
+func (self *Device) FindServices(serviceTypes ...string) (ret []*Service) {
+	self.Walk(func(d *Device) bool {
+		for i := range d.Services {
+			if len(serviceTypes) == 0 ||
+				slices.Contains(serviceTypes, d.Services[i].ServiceType) {
+				ret = append(ret, &d.Services[i])
+			}
+		}
+		return true
+	})
+	return
+}
+
+// This method resolves the passed URL found in the description, such as the
+// controlURL, SCPDURL or eventSubURL of a service or the URL of an icon, as
+// specified by RFC 3986 against URLBase or, in its absence, against descURL,
+// the URL the description was fetched from. A relative URLBase is itself
+// relative to descURL.
+//
+// descURL may be nil, in which case resolution fails unless either URLBase or
+// the passed URL is absolute.
+func (self *DeviceDescription) ResolveURL(raw string, descURL *url.URL) (*url.URL, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, errors.New("Empty URL")
+	}
+	ref, err := url.Parse(escapeZone(raw))
+	if err != nil {
+		return nil, err
+	}
+
+	base := descURL
+	if urlBase := strings.TrimSpace(self.URLBase); urlBase != "" {
+		if base, err = url.Parse(escapeZone(urlBase)); err != nil {
+			return nil, fmt.Errorf("Invalid URLBase: %w", err)
+		}
+		if descURL != nil {
+			base = descURL.ResolveReference(base)
+		}
+	}
+
+	u := ref
+	if base != nil {
+		u = base.ResolveReference(ref)
+	}
+	if !u.IsAbs() || u.Host == "" {
+		return nil, fmt.Errorf("Cannot resolve %q to an absolute URL", raw)
+	}
+	return u, nil
+}
+
+// This method returns the type and control URL, resolved as described in
+// ResolveURL(), of the first service whose type is one of serviceTypes.
+func (self *DeviceDescription) serviceControlURL(descURL *url.URL,
+	serviceTypes ...string) (upnptype string, u *url.URL, err error) {
+	_, service := self.Device.FindService(serviceTypes...)
+	if service == nil {
+		err = errors.New("Control URL not found")
+		return
+	}
+	u, err = self.ResolveURL(service.ControlURL, descURL)
+	return service.ServiceType, u, err
+}
+
+func getConnectionControlURL(body []byte) (upnptype, url string, err error) {
+	return getServiceControlURL(body, connectionTypeStringWANIP,
+		connectionTypeStringWANPPP)
+}
+
+// This function parses the passed device description and returns the type and
+// control URL of the first service whose type is one of serviceTypes, which
+// is only absolute if the description has a URLBase.
+func getServiceControlURL(body []byte, serviceTypes ...string) (upnptype, url string, err error) {
+	x, err := ParseDeviceDescription(body)
+	if err != nil {
+		return
+	}
+	_, service := x.Device.FindService(serviceTypes...)
+	if service == nil {
+		err = errors.New("Control URL not found")
+		return
+	}
+	if u, err := x.ResolveURL(service.ControlURL, nil); err == nil {
+		return service.ServiceType, u.String(), nil
+	}
+	return service.ServiceType, service.ControlURL, nil
+}
@@ -0,0 +1,117 @@
+package goupnp
+
+import (
+	"net"
+	"net/url"
+	"testing"
+)
+
+func TestDeviceDescriptionModel(t *testing.T) {
+	x, err := ParseDeviceDescription([]byte(belkinDescription))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	root := x.Device
+	if root.DeviceType != "urn:schemas-upnp-org:device:InternetGatewayDevice:1" ||
+		root.ModelName != "N150 Wireless Router" || root.ModelNumber != "F9K1001" ||
+		root.SerialNumber != "201223GB303099" ||
+		root.UDN != "uuid:upnp-InternetGatewayDevice-1_0-08863bf24378" ||
+		root.PresentationURL != "/index.html" {
+		t.Errorf("Root device incorrectly parsed as %+v", root)
+	}
+
+	device, service := root.FindService(connectionTypeStringWANIP)
+	if service == nil {
+		t.Fatal("WANIPConnection not found")
+	}
+	if device.DeviceType != "urn:schemas-upnp-org:device:WANConnectionDevice:1" ||
+		service.ServiceID != "urn:upnp-org:serviceId:WANIPConnection" ||
+		service.SCPDURL != "/upnp/service/WANIPCn.xml" ||
+		service.ControlURL != "/upnp/service/WANIPConnection" ||
+		service.EventSubURL != "/upnp/service/WANIPConnection" {
+		t.Errorf("Service incorrectly parsed as %+v on %+v", service, device)
+	}
+
+	if services := root.FindServices(); len(services) != 3 {
+		t.Errorf("Expected 3 services, found %d", len(services))
+	}
+}
+
+func TestResolveURL(t *testing.T) {
+	descURL, _ := url.Parse("http://192.168.1.1:5000/desc/rootDesc.xml")
+	tests := []struct {
+		urlBase, raw, expected string
+	}{
+		// No URLBase, relative to the description URL
+		{"", "/ctl/IPConn", "http://192.168.1.1:5000/ctl/IPConn"},
+		{"", "ctl/IPConn", "http://192.168.1.1:5000/desc/ctl/IPConn"},
+		{"", "../ctl/IPConn", "http://192.168.1.1:5000/ctl/IPConn"},
+		{"", "  /ctl/IPConn\n", "http://192.168.1.1:5000/ctl/IPConn"},
+		// URLBase with and without trailing slashes
+		{"http://192.168.1.1:80", "/ctl/IPConn", "http://192.168.1.1:80/ctl/IPConn"},
+		{"http://192.168.1.1:80/", "/ctl/IPConn", "http://192.168.1.1:80/ctl/IPConn"},
+		{"http://192.168.1.1:80", "ctl/IPConn", "http://192.168.1.1:80/ctl/IPConn"},
+		{"http://192.168.1.1:80/upnp/", "ctl/IPConn", "http://192.168.1.1:80/upnp/ctl/IPConn"},
+		{"http://192.168.1.1:80/upnp", "ctl/IPConn", "http://192.168.1.1:80/ctl/IPConn"},
+		{"http://192.168.1.1:80/upnp/", "/ctl/IPConn", "http://192.168.1.1:80/ctl/IPConn"},
+		// Relative URLBase, itself relative to the description URL
+		{"/upnp/", "ctl/IPConn", "http://192.168.1.1:5000/upnp/ctl/IPConn"},
+		// Absolute URLs ignore both
+		{"", "http://192.168.1.1:49000/ctl/IPConn", "http://192.168.1.1:49000/ctl/IPConn"},
+		{"http://192.168.1.1:80/", "http://192.168.1.1:49000/ctl/IPConn", "http://192.168.1.1:49000/ctl/IPConn"},
+		{"", "//192.168.1.1:49000/ctl/IPConn", "http://192.168.1.1:49000/ctl/IPConn"},
+		// Queries are kept
+		{"", "/ctl?service=IPConn", "http://192.168.1.1:5000/ctl?service=IPConn"},
+		// IPv6 literals with unescaped zones
+		{"http://[fe80::1%br-lan]:5000", "/ctl/IPConn", "http://[fe80::1%25br-lan]:5000/ctl/IPConn"},
+		{"", "http://[2001:db8::1]:5000/ctl/IPConn", "http://[2001:db8::1]:5000/ctl/IPConn"},
+	}
+	for _, test := range tests {
+		x := DeviceDescription{URLBase: test.urlBase}
+		u, err := x.ResolveURL(test.raw, descURL)
+		if err != nil {
+			t.Errorf("Failed to resolve %q against %q: %v", test.raw, test.urlBase, err)
+		} else if u.String() != test.expected {
+			t.Errorf("%q against %q resolved as %v, expected %v", test.raw,
+				test.urlBase, u, test.expected)
+		}
+	}
+
+	failures := []struct {
+		urlBase, raw string
+		descURL      *url.URL
+	}{
+		{"", "", descURL},
+		{"", "/ctl/IPConn", nil},
+		{"/upnp/", "ctl/IPConn", nil},
+		{"http://[::1", "/ctl/IPConn", descURL},
+	}
+	for _, test := range failures {
+		x := DeviceDescription{URLBase: test.urlBase}
+		if u, err := x.ResolveURL(test.raw, test.descURL); err == nil {
+			t.Errorf("%q against %q resolved as %v, expected an error", test.raw,
+				test.urlBase, u)
+		}
+	}
+}
+
+func TestResolveURLInZone(t *testing.T) {
+	descURL, _ := url.Parse("http://[fe80::1%25eth0]:5000/rootDesc.xml")
+	igd := IGD{
+		description: &DeviceDescription{},
+		descURL:     descURL,
+		link:        &net.Interface{Index: 2, Name: "eth0"},
+	}
+	for raw, expected := range map[string]string{
+		"/ctl/IPConn": "http://[fe80::1%25eth0]:5000/ctl/IPConn",
+		"http://[fe80::1%br-lan]:49000/ctl/IPConn": "http://[fe80::1%25eth0]:49000/ctl/IPConn",
+	} {
+		u, err := igd.resolveURL(raw)
+		if err != nil {
+			t.Errorf("Failed to resolve %q: %v", raw, err)
+		} else if u.String() != expected {
+			t.Errorf("%q resolved as %v, expected %v", raw, u, expected)
+		}
+	}
+}
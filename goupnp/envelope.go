@@ -0,0 +1,104 @@
+package goupnp
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+)
+
+const (
+	soapEnvelopeNamespace = "http://schemas.xmlsoap.org/soap/envelope/"
+	soapEncodingStyle     = "http://schemas.xmlsoap.org/soap/encoding/"
+)
+
+// This type is the SOAP envelope of a request. The s: and u: prefixes are
+// spelled out rather than left to encoding/xml, which would instead redeclare
+// the default namespace on every element, something a good many IGDs fail to
+// parse.
+type soapRequestEnvelope struct {
+	XMLName       xml.Name `xml:"s:Envelope"`
+	Namespace     string   `xml:"xmlns:s,attr"`
+	EncodingStyle string   `xml:"s:encodingStyle,attr"`
+	Body          struct {
+		Action soapAction
+	} `xml:"s:Body"`
+}
+
+// This type marshals as the element invoking an action, whose children are
+// the fields of args, typically a struct with one field per in-argument in the
+// order the action expects them, or soapArguments.
+type soapAction struct {
+	serviceType string
+	name        string
+	args        any
+}
+
+func (self soapAction) MarshalXML(e *xml.Encoder, _ xml.StartElement) error {
+	return e.EncodeElement(self.args, xml.StartElement{
+		Name: xml.Name{Local: "u:" + self.name},
+		Attr: []xml.Attr{{
+			Name:  xml.Name{Local: "xmlns:u"},
+			Value: self.serviceType,
+		}},
+	})
+}
+
+type soapArgument struct {
+	Name  string
+	Value string
+}
+
+// This type holds arguments whose names are only known at run time, as is the
+// case for Invoke().
+type soapArguments []soapArgument
+
+func (self soapArguments) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+	for _, argument := range self {
+		err := e.EncodeElement(argument.Value,
+			xml.StartElement{Name: xml.Name{Local: argument.Name}})
+		if err != nil {
+			return err
+		}
+	}
+	return e.EncodeToken(start.End())
+}
+
+// This type marshals as the "1" or "0" every IGD understands rather than the
+// "true" or "false" encoding/xml produces for a bool.
+type soapBool bool
+
+func (self soapBool) MarshalText() ([]byte, error) {
+	return []byte(formatBool(bool(self))), nil
+}
+
+// This function returns the SOAP envelope invoking the passed action of the
+// service of the passed type with the passed arguments, see soapAction.
+// Values are escaped, and invalid UTF-8 replaced, by encoding/xml.
+func marshalSOAP(serviceType, action string, args any) ([]byte, error) {
+	if !isXMLName(action) {
+		return nil, fmt.Errorf("Invalid action name %q", action)
+	}
+	if arguments, ok := args.(soapArguments); ok {
+		for _, argument := range arguments {
+			if !isXMLName(argument.Name) {
+				return nil, fmt.Errorf("Invalid argument name %q", argument.Name)
+			}
+		}
+	}
+
+	envelope := soapRequestEnvelope{
+		Namespace:     soapEnvelopeNamespace,
+		EncodingStyle: soapEncodingStyle,
+	}
+	envelope.Body.Action = soapAction{serviceType, action, args}
+
+	buf := bytes.NewBufferString(`<?xml version="1.0" encoding="utf-8"?>` + "\n")
+	if err := xml.NewEncoder(buf).Encode(&envelope); err != nil {
+		return nil, fmt.Errorf("%s: %w", action, err)
+	}
+	buf.WriteString("\n")
+	return buf.Bytes(), nil
+}
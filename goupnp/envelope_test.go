@@ -0,0 +1,115 @@
+package goupnp
+
+import (
+	"bytes"
+	"encoding/xml"
+	"maps"
+	"testing"
+)
+
+func TestSOAPRequestEscaping(t *testing.T) {
+	description := `<u:Evil/> & "friends" ` + "\xff"
+	envelope, err := marshalSOAP(connectionTypeStringWANIP, "AddPortMapping",
+		&addPortMappingRequest{
+			NewExternalPort:           5900,
+			NewProtocol:               TCP.String(),
+			NewInternalPort:           5901,
+			NewInternalClient:         "192.168.2.5",
+			NewEnabled:                true,
+			NewPortMappingDescription: description,
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// IGDs commonly only understand these exact prefixes
+	for _, expected := range []string{
+		`<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/" ` +
+			`s:encodingStyle="http://schemas.xmlsoap.org/soap/encoding/">`,
+		`<u:AddPortMapping xmlns:u="urn:schemas-upnp-org:service:WANIPConnection:1">`,
+		`<NewRemoteHost></NewRemoteHost><NewExternalPort>5900</NewExternalPort>`,
+		`<NewEnabled>1</NewEnabled>`,
+	} {
+		if !bytes.Contains(envelope, []byte(expected)) {
+			t.Errorf("Envelope does not contain %s:\n%s", expected, envelope)
+		}
+	}
+
+	args, err := parseSOAPResponse(envelope, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The invalid UTF-8 byte is replaced rather than sent as is
+	if actual := args["NewPortMappingDescription"]; actual != description[:len(description)-1]+"\uFFFD" {
+		t.Errorf("Description round-tripped as %q", actual)
+	}
+	if len(args) != 8 {
+		t.Errorf("Expected 8 arguments, got %v", args)
+	}
+}
+
+// The Belkin responses are re-encoded as requests, naming the action after the
+// response element, and must decode to the same values.
+func TestSOAPRequestRoundTrip(t *testing.T) {
+	for _, fixture := range []string{exampleBelkinSOAP, exampleBelkinPortMappingResponse} {
+		var x struct {
+			Body struct {
+				Action struct {
+					XMLName xml.Name
+				} `xml:",any"`
+			}
+		}
+		if err := xml.Unmarshal([]byte(fixture), &x); err != nil {
+			t.Fatal(err)
+		}
+		expected, err := parseSOAPResponse([]byte(fixture), true)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// The arguments are sent in the order of the fixture
+		var arguments soapArguments
+		decoder := xml.NewDecoder(bytes.NewReader([]byte(fixture)))
+		for token, err := decoder.Token(); err == nil; token, err = decoder.Token() {
+			if start, ok := token.(xml.StartElement); ok {
+				if value, ok := expected[start.Name.Local]; ok {
+					arguments = append(arguments, soapArgument{start.Name.Local, value})
+				}
+			}
+		}
+
+		action := x.Body.Action.XMLName
+		envelope, err := marshalSOAP(action.Space, action.Local, arguments)
+		if err != nil {
+			t.Fatal(err)
+		}
+		actual, err := parseSOAPResponse(envelope, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !maps.Equal(actual, expected) {
+			t.Errorf("Arguments round-tripped as %v, expected %v", actual, expected)
+		}
+
+		var reference, roundTripped soapEnvelope
+		if err := xml.Unmarshal([]byte(fixture), &reference); err != nil {
+			t.Fatal(err)
+		}
+		if err := xml.Unmarshal(envelope, &roundTripped); err != nil {
+			t.Fatal(err)
+		}
+		if roundTripped.Body != reference.Body {
+			t.Errorf("Envelope decoded as %+v, expected %+v", roundTripped.Body, reference.Body)
+		}
+	}
+}
+
+func TestSOAPRequestInvalidNames(t *testing.T) {
+	if _, err := marshalSOAP(connectionTypeStringWANIP, "Bad><Action", struct{}{}); err == nil {
+		t.Error("Expected an error for an invalid action name")
+	}
+	if _, err := marshalSOAP(connectionTypeStringWANIP, "GetStatusInfo",
+		soapArguments{{"Bad Name", ""}}); err == nil {
+		t.Error("Expected an error for an invalid argument name")
+	}
+}
@@ -0,0 +1,175 @@
+package goupnp
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"log/slog"
+)
+
+// The lease requested for pinholes created by Expose(), the maximum allowed
+// by WANIPv6FirewallControl. Pinholes are renewed at half this interval for as
+// long as the Exposure is not closed.
+const exposeLease = 86400
+
+// This type describes an address on which an exposed port is reachable from
+// the internet.
+type Endpoint struct {
+	IP       net.IP
+	Port     uint16
+	Protocol protocol
+}
+
+func (self *Endpoint) String() string {
+	return fmt.Sprint(net.JoinHostPort(self.IP.String(), fmt.Sprint(self.Port)),
+		"/", self.Protocol)
+}
+
+// This type is returned by Expose() and groups the IPv4 port mapping and the
+// IPv6 pinhole making a local port reachable, either of which may be nil if
+// it could not be created. Use Close() to tear them both down.
+type Exposure struct {
+	Mapping   *PortMapping
+	Pinhole   *Pinhole
+	Endpoints []*Endpoint
+
+	igd       *IGD
+	stop      chan struct{}
+	closeOnce sync.Once
+}
+
+// This method makes the passed local port reachable from the internet on both
+// address families: it creates an IPv4 port mapping as
+// AddLocalPortRedirection() does, unless the IGD was found over IPv6 on an
+// interface without any IPv4 address, and, if the IGD provides
+// WANIPv6FirewallControl, a pinhole for the global IPv6 address of the host.
+//
+// Errors are indicated by the channel closing before an Exposure is returned,
+// which only happens when neither could be created. Otherwise the Endpoints
+// of the returned Exposure list every public address the port is reachable on.
+func (self *IGD) Expose(port uint16, proto protocol) (ret chan *Exposure) {
+	ret = make(chan *Exposure)
+
+	go func() {
+		exposure := Exposure{igd: self, stop: make(chan struct{})}
+
+		if self.internalClient == nil {
+			// Found over IPv6 on an interface without any IPv4 address
+			slog.Debug("No IPv4 address to map the port to", "iface", self.iface)
+		} else if mapping, ok := <-self.AddLocalPortRedirection(port, proto); ok {
+			exposure.Mapping = mapping
+			if status, ok := <-self.GetConnectionStatus(); ok && status.Connected {
+				exposure.Endpoints = append(exposure.Endpoints,
+					&Endpoint{status.IP, mapping.ExternalPort, proto})
+			}
+		} else {
+			slog.Warn("Failed to create IPv4 port mapping", "port", port, "protocol", proto)
+		}
+
+		if ip := globalIPv6Addr(self.localAddrs()); ip != nil && self.HasFirewallControl() {
+			pinhole, ok := <-self.AddPinhole(&Pinhole{
+				InternalClient: ip,
+				InternalPort:   port,
+				Protocol:       proto,
+				Lease:          exposeLease,
+			})
+			if ok {
+				exposure.Pinhole = pinhole
+				exposure.Endpoints = append(exposure.Endpoints,
+					&Endpoint{ip, port, proto})
+				go exposure.renewPinhole()
+			} else {
+				slog.Warn("Failed to create IPv6 pinhole", "ip", ip, "port", port, "protocol", proto)
+			}
+		}
+
+		if exposure.Mapping != nil || exposure.Pinhole != nil {
+			ret <- &exposure
+		}
+		close(ret)
+	}()
+
+	return
+}
+
+// This method deletes the port mapping and pinhole of the Exposure. It blocks
+// until the IGD has answered and returns the errors it reported, if any.
+// Calling it more than once has no effect.
+func (self *Exposure) Close() (err error) {
+	self.closeOnce.Do(func() {
+		close(self.stop)
+		var errs []error
+		if self.Mapping != nil {
+			errs = append(errs, <-self.igd.DeletePortRedirection(self.Mapping))
+		}
+		if self.Pinhole != nil {
+			errs = append(errs, <-self.igd.DeletePinhole(self.Pinhole))
+		}
+		err = errors.Join(errs...)
+	})
+	return
+}
+
+// This method keeps the pinhole of the Exposure open until it is closed, as
+// unlike IPv4 port mappings pinholes always expire.
+func (self *Exposure) renewPinhole() {
+	ticker := time.NewTicker(exposeLease * time.Second / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-self.stop:
+			return
+		case <-ticker.C:
+			if err := <-self.igd.UpdatePinhole(self.Pinhole, exposeLease); err != nil {
+				slog.Warn("Failed to renew pinhole", "pinhole", self.Pinhole, "error", err)
+			}
+		}
+	}
+}
+
+// This method returns the addresses of the local interface the IGD was
+// discovered on, or nil if that interface cannot be determined.
+func (self *IGD) localAddrs() []net.Addr {
+	iface := self.link
+	if iface == nil {
+		ifaces, err := net.Interfaces()
+		if err != nil {
+			return nil
+		}
+		for i := range ifaces {
+			if interfaceHasIP(&ifaces[i], self.iface) {
+				iface = &ifaces[i]
+				break
+			}
+		}
+		if iface == nil {
+			return nil
+		}
+	}
+	addrs, err := iface.Addrs()
+	if err != nil {
+		return nil
+	}
+	return addrs
+}
+
+// This function returns the first global IPv6 address among the passed
+// interface addresses, or nil if there is none. Unique local addresses are not
+// considered as they are not reachable from the internet.
+func globalIPv6Addr(addrs []net.Addr) net.IP {
+	for _, addr := range addrs {
+		if ipNet, ok := addr.(*net.IPNet); ok && isGlobalIPv6(ipNet.IP) {
+			return ipNet.IP
+		}
+	}
+	return nil
+}
+
+// This function returns true if and only if the passed address is an IPv6
+// address reachable from the internet.
+func isGlobalIPv6(ip net.IP) bool {
+	return ip.To4() == nil && ip.IsGlobalUnicast() && !ip.IsPrivate()
+}
@@ -0,0 +1,104 @@
+package goupnp
+
+import (
+	"net"
+	"testing"
+)
+
+func TestIsGlobalIPv6(t *testing.T) {
+	tests := []struct {
+		ip     net.IP
+		global bool
+	}{
+		{net.ParseIP("2001:db8::1"), true},
+		{net.ParseIP("2a01:e0a:1:2::3"), true},
+		{net.ParseIP("fd00::1"), false},
+		{net.ParseIP("fe80::1"), false},
+		{net.ParseIP("::1"), false},
+		{net.IPv4(8, 8, 8, 8), false},
+	}
+	for _, test := range tests {
+		if global := isGlobalIPv6(test.ip); global != test.global {
+			t.Errorf("isGlobalIPv6(%v) = %v, expected %v", test.ip, global, test.global)
+		}
+	}
+}
+
+func TestGlobalIPv6Addr(t *testing.T) {
+	ipNet := func(cidr string) net.Addr {
+		ip, ipNet, _ := net.ParseCIDR(cidr)
+		ipNet.IP = ip
+		return ipNet
+	}
+	addrs := []net.Addr{
+		ipNet("192.168.1.10/24"),
+		ipNet("fe80::1/64"),
+		ipNet("fd00::1/64"),
+		ipNet("2001:db8::1/64"),
+	}
+	if ip := globalIPv6Addr(addrs); !ip.Equal(net.ParseIP("2001:db8::1")) {
+		t.Errorf("Expected the global address, got %v", ip)
+	}
+	if ip := globalIPv6Addr(addrs[:3]); ip != nil {
+		t.Errorf("Expected no address, got %v", ip)
+	}
+}
+
+func TestLocalAddrsUnknownInterface(t *testing.T) {
+	// An address no interface holds must not fall back to another interface
+	igd := &IGD{iface: net.ParseIP("2001:db8::dead")}
+	if addrs := igd.localAddrs(); addrs != nil {
+		t.Errorf("Expected no addresses, got %v", addrs)
+	}
+}
+
+func TestEndpointString(t *testing.T) {
+	endpoints := map[string]*Endpoint{
+		"203.0.113.7:8080/TCP":   {net.IPv4(203, 0, 113, 7), 8080, TCP},
+		"[2001:db8::5]:8080/UDP": {net.ParseIP("2001:db8::5"), 8080, UDP},
+	}
+	for expected, endpoint := range endpoints {
+		if str := endpoint.String(); str != expected {
+			t.Errorf("Endpoint formatted as %v, expected %v", str, expected)
+		}
+	}
+}
+
+func TestExpose(t *testing.T) {
+	var actions []string
+	handler := newSOAPTestHandler(t, func(action string, args map[string]string) map[string]string {
+		actions = append(actions, action)
+		switch action {
+		case "AddPortMapping", "DeletePortMapping":
+			return map[string]string{}
+		case "GetStatusInfo":
+			return map[string]string{"NewConnectionStatus": "Connected"}
+		case "GetExternalIPAddress":
+			return map[string]string{"NewExternalIPAddress": "203.0.113.7"}
+		}
+		return nil
+	})
+
+	igd := newTestIGDWith(t, loopbackInterface, belkinDescription, nil, handler)
+	exposure, ok := <-igd.Expose(8080, TCP)
+	if !ok || exposure.Mapping == nil || len(exposure.Endpoints) != 1 ||
+		exposure.Endpoints[0].String() != "203.0.113.7:8080/TCP" {
+		t.Fatalf("Port exposed as %+v", exposure)
+	}
+	if err := exposure.Close(); err != nil {
+		t.Error(err)
+	}
+
+	// Found over IPv6 on an interface without any IPv4 address, and without
+	// WANIPv6FirewallControl, there is nothing to expose
+	actions = nil
+	igd = newTestIGDWith(t, localInterface{
+		addr: &net.UDPAddr{IP: net.ParseIP("fe80::1"), Zone: "eth0"},
+	}, belkinDescription, nil, handler)
+	if exposure, ok := <-igd.Expose(8080, TCP); ok {
+		t.Errorf("Port exposed as %+v for a link-local address", exposure)
+	}
+	if len(actions) != 0 {
+		t.Errorf("Invoked %v", actions)
+	}
+}
@@ -0,0 +1,266 @@
+package goupnp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+)
+
+// This type describes an IPv6 firewall pinhole, the IPv6 counterpart of a
+// PortMapping. It also serves as a handle returned by AddPinhole() for use
+// with UpdatePinhole(), DeletePinhole() and the like.
+//
+// A nil RemoteHost and a zero RemotePort respectively allow traffic from any
+// host and any port.
+type Pinhole struct {
+	UniqueID       uint16
+	RemoteHost     net.IP
+	RemotePort     uint16
+	InternalClient net.IP
+	InternalPort   uint16
+	Protocol       protocol
+	Lease          uint
+}
+
+func (self *Pinhole) String() string {
+	remoteHost := "*"
+	if self.RemoteHost != nil {
+		remoteHost = self.RemoteHost.String()
+	}
+	return fmt.Sprint("[", self.InternalClient, "]:", self.InternalPort, "<=",
+		"[", remoteHost, "]:", self.RemotePort, self.Protocol, " #",
+		self.UniqueID, " (", self.Lease, ")")
+}
+
+// This type reports whether the IGD filters inbound IPv6 traffic and if so
+// whether it allows creating pinholes through its firewall.
+type FirewallStatus struct {
+	Enabled               bool
+	InboundPinholeAllowed bool
+}
+
+// The error sent on the channels returned by the pinhole methods when the IGD
+// does not implement the WANIPv6FirewallControl service.
+var ErrNoFirewallControl = errors.New("IGD does not provide WANIPv6FirewallControl")
+
+// This method returns true if and only if the IGD provides the
+// WANIPv6FirewallControl service required by the pinhole methods.
+func (self *IGD) HasFirewallControl() bool {
+	return self.firewallURL != nil
+}
+
+// This method fetches the status of the IPv6 firewall of the IGD.
+//
+// Errors are indicated by the channel closing before a FirewallStatus is
+// returned, in particular if the IGD does not provide the
+// WANIPv6FirewallControl service.
+func (self *IGD) GetFirewallStatus() (ret chan *FirewallStatus) {
+	ret = make(chan *FirewallStatus)
+
+	go func() {
+		x, ok := self.firewallRequest("GetFirewallStatus",
+			struct{}{})
+		if ok {
+			ret <- &FirewallStatus{
+				Enabled:               parseUPnPBool(x.Body.FirewallStatus.FirewallEnabled),
+				InboundPinholeAllowed: parseUPnPBool(x.Body.FirewallStatus.InboundPinholeAllowed),
+			}
+		}
+		close(ret)
+	}()
+
+	return
+}
+
+// This method returns the time after which the IGD closes outbound pinholes
+// matching the passed pinhole, that is how often traffic must flow to keep
+// them open. The UniqueID and Lease of the pinhole are ignored.
+//
+// Errors are indicated by the channel closing before a duration is returned.
+func (self *IGD) GetOutboundPinholeTimeout(pinhole *Pinhole) (ret chan time.Duration) {
+	ret = make(chan time.Duration)
+
+	go func() {
+		x, ok := self.firewallRequest("GetOutboundPinholeTimeout",
+			newPinholeRequest(pinhole))
+		if ok {
+			ret <- time.Duration(x.Body.OutboundPinholeTimeout.OutboundPinholeTimeout) * time.Second
+		}
+		close(ret)
+	}()
+
+	return
+}
+
+// This method opens a pinhole in the IPv6 firewall of the IGD as described by
+// the passed pinhole, whose UniqueID is ignored. Lease must be non-zero as the
+// standard requires all pinholes to expire.
+//
+// Errors are indicated by the channel closing before a Pinhole is returned.
+// The returned Pinhole is a copy of the one passed with UniqueID set to the
+// identifier the IGD assigned.
+func (self *IGD) AddPinhole(pinhole *Pinhole) (ret chan *Pinhole) {
+	ret = make(chan *Pinhole)
+
+	go func() {
+		x, ok := self.firewallRequest("AddPinhole",
+			&addPinholeRequest{newPinholeRequest(pinhole), pinhole.Lease})
+		if ok {
+			added := *pinhole
+			added.UniqueID = x.Body.Pinhole.UniqueID
+			ret <- &added
+		}
+		close(ret)
+	}()
+
+	return
+}
+
+// This method extends the lease of the passed pinhole to lease seconds.
+//
+// The channel is sent nil on success and an error otherwise before being
+// closed.
+func (self *IGD) UpdatePinhole(pinhole *Pinhole, lease uint) (ret chan error) {
+	ret = make(chan error, 1)
+
+	go func() {
+		_, ok := self.firewallRequest("UpdatePinhole",
+			&updatePinholeRequest{pinhole.UniqueID, lease})
+		if ok {
+			pinhole.Lease = lease
+			ret <- nil
+		} else {
+			ret <- self.firewallError("UpdatePinhole")
+		}
+		close(ret)
+	}()
+
+	return
+}
+
+// This method closes each of the passed pinholes.
+//
+// The channel is sent one value per pinhole, in order, nil on success and an
+// error otherwise, before being closed.
+func (self *IGD) DeletePinhole(pinholes ...*Pinhole) (ret chan error) {
+	ret = make(chan error, len(pinholes))
+
+	go func() {
+		for _, pinhole := range pinholes {
+			_, ok := self.firewallRequest("DeletePinhole",
+				&uniqueIDRequest{pinhole.UniqueID})
+			if ok {
+				ret <- nil
+			} else {
+				ret <- self.firewallError("DeletePinhole")
+			}
+		}
+		close(ret)
+	}()
+
+	return
+}
+
+// This method returns the number of packets which went through the passed
+// pinhole.
+//
+// Errors are indicated by the channel closing before a count is returned.
+func (self *IGD) GetPinholePackets(pinhole *Pinhole) (ret chan uint) {
+	ret = make(chan uint)
+
+	go func() {
+		x, ok := self.firewallRequest("GetPinholePackets",
+			&uniqueIDRequest{pinhole.UniqueID})
+		if ok {
+			ret <- x.Body.PinholePackets.PinholePackets
+		}
+		close(ret)
+	}()
+
+	return
+}
+
+// This method asks the IGD whether traffic actually goes through the passed
+// pinhole.
+//
+// Errors are indicated by the channel closing before a value is returned.
+// Many IGDs do not implement this optional action.
+func (self *IGD) CheckPinholeWorking(pinhole *Pinhole) (ret chan bool) {
+	ret = make(chan bool)
+
+	go func() {
+		x, ok := self.firewallRequest("CheckPinholeWorking",
+			&uniqueIDRequest{pinhole.UniqueID})
+		if ok {
+			ret <- parseUPnPBool(x.Body.PinholeWorking.IsWorking)
+		}
+		close(ret)
+	}()
+
+	return
+}
+
+func (self *IGD) firewallRequest(requestType string,
+	args any) (x *soapEnvelope, ok bool) {
+	if self.firewallURL == nil {
+		return
+	}
+	return self.soapRequestTo(context.Background(), self.firewallURL,
+		firewallTypeString, requestType, args)
+}
+
+func (self *IGD) firewallError(requestType string) error {
+	if self.firewallURL == nil {
+		return ErrNoFirewallControl
+	}
+	return fmt.Errorf("%s request failed", requestType)
+}
+
+// This function formats the passed IP address as expected by the
+// WANIPv6FirewallControl service, that is with the empty string standing for
+// any host.
+func pinholeHost(ip net.IP) string {
+	if ip == nil {
+		return ""
+	}
+	return ip.String()
+}
+
+// This type holds the arguments identifying a pinhole which
+// GetOutboundPinholeTimeout and AddPinhole have in common, the latter also
+// taking a LeaseTime.
+type pinholeRequest struct {
+	RemoteHost     string
+	RemotePort     uint16
+	InternalClient string
+	InternalPort   uint16
+	Protocol       uint16
+}
+
+func newPinholeRequest(pinhole *Pinhole) pinholeRequest {
+	return pinholeRequest{
+		RemoteHost:     pinholeHost(pinhole.RemoteHost),
+		RemotePort:     pinhole.RemotePort,
+		InternalClient: pinholeHost(pinhole.InternalClient),
+		InternalPort:   pinhole.InternalPort,
+		Protocol:       pinhole.Protocol.number(),
+	}
+}
+
+type addPinholeRequest struct {
+	pinholeRequest
+	LeaseTime uint
+}
+
+type updatePinholeRequest struct {
+	UniqueID     uint16
+	NewLeaseTime uint
+}
+
+// DeletePinhole, GetPinholePackets and CheckPinholeWorking all take the
+// UniqueID of the pinhole as sole argument, hence this shared type.
+type uniqueIDRequest struct {
+	UniqueID uint16
+}
@@ -0,0 +1,111 @@
+package goupnp
+
+import (
+	"encoding/xml"
+	"net"
+	"strings"
+	"testing"
+)
+
+const exampleFirewallStatusResponse = `<?xml version="1.0"?>
+<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/"
+s:encodingStyle="http://schemas.xmlsoap.org/soap/encoding/"><s:Body>
+<u:GetFirewallStatusResponse
+xmlns:u="urn:schemas-upnp-org:service:WANIPv6FirewallControl:1">
+<FirewallEnabled>1</FirewallEnabled>
+<InboundPinholeAllowed>true</InboundPinholeAllowed>
+</u:GetFirewallStatusResponse></s:Body></s:Envelope>
+`
+
+const exampleAddPinholeResponse = `<?xml version="1.0"?>
+<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/"
+s:encodingStyle="http://schemas.xmlsoap.org/soap/encoding/"><s:Body>
+<u:AddPinholeResponse
+xmlns:u="urn:schemas-upnp-org:service:WANIPv6FirewallControl:1">
+<UniqueID>42</UniqueID>
+</u:AddPinholeResponse></s:Body></s:Envelope>
+`
+
+func TestFirewallResponseParsing(t *testing.T) {
+	var x soapEnvelope
+	if err := xml.Unmarshal([]byte(exampleFirewallStatusResponse), &x); err != nil {
+		t.Fatal(err)
+	}
+	if !parseUPnPBool(x.Body.FirewallStatus.FirewallEnabled) ||
+		!parseUPnPBool(x.Body.FirewallStatus.InboundPinholeAllowed) {
+		t.Errorf("Firewall status incorrectly parsed as %+v", x.Body.FirewallStatus)
+	}
+
+	x = soapEnvelope{}
+	if err := xml.Unmarshal([]byte(exampleAddPinholeResponse), &x); err != nil {
+		t.Fatal(err)
+	}
+	if id := x.Body.Pinhole.UniqueID; id != 42 {
+		t.Errorf("UniqueID incorrectly parsed as %v", id)
+	}
+}
+
+func TestAddPinholeRequest(t *testing.T) {
+	pinhole := &Pinhole{
+		InternalClient: net.ParseIP("2001:db8::5"),
+		InternalPort:   8080,
+		Protocol:       UDP,
+		Lease:          3600,
+	}
+	body, err := marshalSOAP(firewallTypeString, "AddPinhole",
+		&addPinholeRequest{newPinholeRequest(pinhole), pinhole.Lease})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var x struct {
+		Body struct {
+			AddPinhole struct {
+				RemoteHost     string
+				RemotePort     uint16
+				InternalClient string
+				InternalPort   uint16
+				Protocol       uint16
+				LeaseTime      uint
+			}
+		}
+	}
+	if err := xml.Unmarshal(body, &x); err != nil {
+		t.Fatal(err)
+	}
+	args := x.Body.AddPinhole
+	if args.RemoteHost != "" || args.RemotePort != 0 ||
+		args.InternalClient != "2001:db8::5" || args.InternalPort != 8080 ||
+		args.Protocol != 17 || args.LeaseTime != 3600 {
+		t.Errorf("Request incorrectly formatted as %s", body)
+	}
+}
+
+func TestFirewallControlURL(t *testing.T) {
+	const description = `<?xml version="1.0"?>
+<root xmlns="urn:schemas-upnp-org:device-1-0">
+	<specVersion><major>1</major><minor>0</minor></specVersion>
+	<URLBase>http://192.168.1.1:5000</URLBase>
+	<device>
+		<deviceList><device>
+			<deviceList><device>
+				<serviceList>
+					<service>
+						<serviceType>urn:schemas-upnp-org:service:WANIPConnection:2</serviceType>
+						<controlURL>/ctl/IPConn</controlURL>
+					</service>
+					<service>
+						<serviceType>urn:schemas-upnp-org:service:WANIPv6FirewallControl:1</serviceType>
+						<controlURL>/ctl/IP6FCtl</controlURL>
+					</service>
+				</serviceList>
+			</device></deviceList>
+		</device></deviceList>
+	</device>
+</root>
+`
+	_, url, err := getServiceControlURL([]byte(description), firewallTypeString)
+	if err != nil || !strings.HasSuffix(url, "/ctl/IP6FCtl") {
+		t.Errorf("URL: %v, Error: %v", url, err)
+	}
+}
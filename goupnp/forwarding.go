@@ -0,0 +1,109 @@
+package goupnp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// The error returned by the default connection service methods when the IGD
+// does not implement the Layer3Forwarding service.
+var ErrNoLayer3Forwarding = errors.New("IGD does not provide Layer3Forwarding")
+
+// This method returns a client for the Layer3Forwarding service of the IGD,
+// which the specification places in the root InternetGatewayDevice.
+func (self *IGD) Layer3Forwarding() (*Layer3Forwarding1, error) {
+	_, service := self.description.Device.FindService(Layer3Forwarding1ServiceType)
+	if service == nil {
+		return nil, ErrNoLayer3Forwarding
+	}
+	return &Layer3Forwarding1{igd: self, Service: service}, nil
+}
+
+// This method returns the connection service carrying the default route of
+// the IGD as reported by its Layer3Forwarding service. The returned service is
+// one of ConnectionServices(), to be passed to SelectConnectionService().
+func (self *IGD) GetDefaultConnectionService(ctx context.Context) (*Service, error) {
+	client, err := self.Layer3Forwarding()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.GetDefaultConnectionService(ctx)
+	if err != nil {
+		return nil, err
+	}
+	service := self.findDefaultConnectionService(resp.NewDefaultConnectionService)
+	if service == nil {
+		return nil, fmt.Errorf("Unknown default connection service %q",
+			resp.NewDefaultConnectionService)
+	}
+	return service, nil
+}
+
+// This method makes the passed connection service, which must be one of
+// ConnectionServices(), the one carrying the default route of the IGD.
+func (self *IGD) SetDefaultConnectionService(ctx context.Context, service *Service) error {
+	client, err := self.Layer3Forwarding()
+	if err != nil {
+		return err
+	}
+	path := devicePath(&self.description.Device, service)
+	if path == nil || !isConnectionService(service) {
+		return errors.New("Not a connection service of this IGD")
+	}
+	device := path[len(path)-1]
+	return client.SetDefaultConnectionService(ctx,
+		&Layer3Forwarding1SetDefaultConnectionServiceRequest{
+			NewDefaultConnectionService: device.UDN + ":" +
+				shortDeviceType(device.DeviceType) + "," + service.ServiceID,
+		})
+}
+
+// This method returns the connection service the passed value of the
+// DefaultConnectionService state variable designates, or nil. The value is
+// formed as "uuid:<UDN>:WANConnectionDevice:1,urn:upnp-org:serviceId:WANPPPConn1"
+// but IGDs are known to report a bogus device part, in which case the service
+// identifier alone is used, provided it is unambiguous.
+func (self *IGD) findDefaultConnectionService(value string) *Service {
+	devicePart, serviceID, ok := strings.Cut(strings.TrimSpace(value), ",")
+	if !ok {
+		return nil
+	}
+	devicePart, serviceID = strings.TrimSpace(devicePart), strings.TrimSpace(serviceID)
+
+	var candidates []*Service
+	for _, service := range self.ConnectionServices() {
+		if service.ServiceID != serviceID {
+			continue
+		}
+		path := devicePath(&self.description.Device, service)
+		if udn := path[len(path)-1].UDN; udn != "" &&
+			(devicePart == udn || strings.HasPrefix(devicePart, udn+":")) {
+			return service
+		}
+		candidates = append(candidates, service)
+	}
+	if len(candidates) == 1 {
+		return candidates[0]
+	}
+	return nil
+}
+
+// This function returns true if and only if the passed service is a
+// WANIPConnection or WANPPPConnection service.
+func isConnectionService(service *Service) bool {
+	return service.ServiceType == connectionTypeStringWANIP ||
+		service.ServiceType == connectionTypeStringWANPPP
+}
+
+// This function returns the passed device type without its domain, e.g.
+// "WANConnectionDevice:1" for
+// "urn:schemas-upnp-org:device:WANConnectionDevice:1".
+func shortDeviceType(deviceType string) string {
+	fields := strings.Split(deviceType, ":")
+	if len(fields) < 2 {
+		return deviceType
+	}
+	return strings.Join(fields[len(fields)-2:], ":")
+}
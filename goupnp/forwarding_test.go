@@ -0,0 +1,147 @@
+package goupnp
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// A router with two WANConnectionDevices both reporting being Connected and a
+// Layer3Forwarding service.
+const exampleForwardingDescription = `<?xml version="1.0"?>
+<root xmlns="urn:schemas-upnp-org:device-1-0">
+	<specVersion><major>1</major><minor>0</minor></specVersion>
+	<URLBase>http://192.168.1.1:5000</URLBase>
+	<device>
+		<deviceType>urn:schemas-upnp-org:device:InternetGatewayDevice:1</deviceType>
+		<UDN>uuid:igd</UDN>
+		<serviceList><service>
+			<serviceType>urn:schemas-upnp-org:service:Layer3Forwarding:1</serviceType>
+			<serviceId>urn:upnp-org:serviceId:L3Forwarding1</serviceId>
+			<controlURL>/ctl/L3F</controlURL>
+		</service></serviceList>
+		<deviceList><device>
+			<deviceType>urn:schemas-upnp-org:device:WANDevice:1</deviceType>
+			<UDN>uuid:wan</UDN>
+			<deviceList>
+				<device>
+					<deviceType>urn:schemas-upnp-org:device:WANConnectionDevice:1</deviceType>
+					<UDN>uuid:conn1</UDN>
+					<serviceList><service>
+						<serviceType>urn:schemas-upnp-org:service:WANIPConnection:1</serviceType>
+						<serviceId>urn:upnp-org:serviceId:WANIPConn1</serviceId>
+						<controlURL>/ctl/IPConn</controlURL>
+					</service></serviceList>
+				</device>
+				<device>
+					<deviceType>urn:schemas-upnp-org:device:WANConnectionDevice:1</deviceType>
+					<UDN>uuid:conn2</UDN>
+					<serviceList><service>
+						<serviceType>urn:schemas-upnp-org:service:WANPPPConnection:1</serviceType>
+						<serviceId>urn:upnp-org:serviceId:WANPPPConn1</serviceId>
+						<controlURL>/ctl/PPPConn</controlURL>
+					</service></serviceList>
+				</device>
+			</deviceList>
+		</device></deviceList>
+	</device>
+</root>
+`
+
+func TestDefaultConnectionService(t *testing.T) {
+	defaultConnection := "uuid:conn2:WANConnectionDevice:1,urn:upnp-org:serviceId:WANPPPConn1"
+	igd := newTestIGDWith(t, loopbackInterface, exampleForwardingDescription, nil,
+		newSOAPTestHandler(t, func(action string, args map[string]string) map[string]string {
+			switch action {
+			case "GetStatusInfo":
+				return map[string]string{"NewConnectionStatus": "Connected"}
+			case "GetDefaultConnectionService":
+				return map[string]string{"NewDefaultConnectionService": defaultConnection}
+			case "SetDefaultConnectionService":
+				defaultConnection = args["NewDefaultConnectionService"]
+				return map[string]string{}
+			}
+			return nil
+		}))
+	services := igd.ConnectionServices()
+	if igd.ConnectionService() != services[1] {
+		t.Errorf("Bound to %v rather than the default PPP service", igd)
+	}
+
+	ctx := context.Background()
+	if err := igd.SetDefaultConnectionService(ctx, services[0]); err != nil {
+		t.Fatal(err)
+	}
+	if defaultConnection != "uuid:conn1:WANConnectionDevice:1,urn:upnp-org:serviceId:WANIPConn1" {
+		t.Errorf("Default connection service set as %q", defaultConnection)
+	}
+	if service, err := igd.GetDefaultConnectionService(ctx); err != nil || service != services[0] {
+		t.Errorf("Default connection service reported as %v, %v", service, err)
+	}
+
+	// Some IGDs report a bogus device part
+	defaultConnection = "uuid:bogus:WANConnectionDevice:1,urn:upnp-org:serviceId:WANPPPConn1"
+	if service, err := igd.GetDefaultConnectionService(ctx); err != nil || service != services[1] {
+		t.Errorf("Default connection service reported as %v, %v", service, err)
+	}
+	defaultConnection = "uuid:conn1:WANConnectionDevice:1,urn:upnp-org:serviceId:Other"
+	if service, err := igd.GetDefaultConnectionService(ctx); err == nil {
+		t.Errorf("Unknown default connection service reported as %v", service)
+	}
+
+	l3f, _ := igd.Layer3Forwarding()
+	if err := igd.SetDefaultConnectionService(ctx, l3f.Service); err == nil {
+		t.Error("Expected an error setting a non-connection service as default")
+	}
+}
+
+func TestNoLayer3Forwarding(t *testing.T) {
+	igd := &IGD{description: &DeviceDescription{}}
+	if _, err := igd.GetDefaultConnectionService(context.Background()); !errors.Is(err, ErrNoLayer3Forwarding) {
+		t.Errorf("Expected ErrNoLayer3Forwarding, got %v", err)
+	}
+}
+
+func TestShortDeviceType(t *testing.T) {
+	types := map[string]string{
+		"urn:schemas-upnp-org:device:WANConnectionDevice:1": "WANConnectionDevice:1",
+		"WANConnectionDevice:1":                             "WANConnectionDevice:1",
+		"Bogus":                                             "Bogus",
+	}
+	for deviceType, expected := range types {
+		if actual := shortDeviceType(deviceType); actual != expected {
+			t.Errorf("%q shortened as %q, expected %q", deviceType, actual, expected)
+		}
+	}
+}
+
+func TestFindDefaultConnectionServiceUDNPrefix(t *testing.T) {
+	connectionDevice := func(udn string) Device {
+		return Device{
+			DeviceType: "urn:schemas-upnp-org:device:WANConnectionDevice:1",
+			UDN:        udn,
+			Services: []Service{{
+				ServiceType: connectionTypeStringWANIP,
+				ServiceID:   "urn:upnp-org:serviceId:WANIPConn1",
+			}},
+		}
+	}
+	// The UDN of the first device is a prefix of that of the second
+	igd := &IGD{description: &DeviceDescription{Device: Device{
+		Devices: []Device{connectionDevice("uuid:1"), connectionDevice("uuid:12")},
+	}}}
+	services := igd.ConnectionServices()
+
+	values := map[string]*Service{
+		"uuid:1:WANConnectionDevice:1,urn:upnp-org:serviceId:WANIPConn1":  services[0],
+		"uuid:12:WANConnectionDevice:1,urn:upnp-org:serviceId:WANIPConn1": services[1],
+		"uuid:12,urn:upnp-org:serviceId:WANIPConn1":                       services[1],
+		// Ambiguous without a matching device
+		"uuid:123:WANConnectionDevice:1,urn:upnp-org:serviceId:WANIPConn1": nil,
+	}
+	for value, expected := range values {
+		if actual := igd.findDefaultConnectionService(value); actual != expected {
+			t.Errorf("%s designates %v, expected %v", value, actual, expected)
+		}
+	}
+}
@@ -0,0 +1,382 @@
+// Code generated by upnpgen from scpd/LANHostConfigManagement1.xml. DO NOT EDIT.
+
+package goupnp
+
+import (
+	"context"
+	"fmt"
+)
+
+// The type of the services LANHostConfigManagement1 is a client for.
+const LANHostConfigManagement1ServiceType = "urn:schemas-upnp-org:service:LANHostConfigManagement:1"
+
+// This type is a client for a service of type LANHostConfigManagement1ServiceType of an
+// IGD. Use IGD.LANHostConfigManagement1Clients() to obtain one.
+type LANHostConfigManagement1 struct {
+	igd     *IGD
+	Service *Service
+}
+
+// This method returns a client for every service of type
+// LANHostConfigManagement1ServiceType of the IGD, in the order they appear in its
+// description.
+func (self *IGD) LANHostConfigManagement1Clients() (ret []*LANHostConfigManagement1) {
+	for _, service := range self.description.Device.FindServices(LANHostConfigManagement1ServiceType) {
+		ret = append(ret, &LANHostConfigManagement1{igd: self, Service: service})
+	}
+	return
+}
+
+// The in-arguments of the SetDHCPServerConfigurable action.
+type LANHostConfigManagement1SetDHCPServerConfigurableRequest struct {
+	NewDHCPServerConfigurable bool // DHCPServerConfigurable
+}
+
+// This method invokes the SetDHCPServerConfigurable action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *LANHostConfigManagement1) SetDHCPServerConfigurable(ctx context.Context, request *LANHostConfigManagement1SetDHCPServerConfigurableRequest) error {
+	_, err := self.igd.Invoke(ctx, self.Service, "SetDHCPServerConfigurable", map[string]string{
+		"NewDHCPServerConfigurable": formatBool(request.NewDHCPServerConfigurable),
+	})
+	if err != nil {
+		return fmt.Errorf("SetDHCPServerConfigurable: %w", err)
+	}
+	return nil
+}
+
+// The out-arguments of the GetDHCPServerConfigurable action.
+type LANHostConfigManagement1GetDHCPServerConfigurableResponse struct {
+	NewDHCPServerConfigurable bool // DHCPServerConfigurable
+}
+
+// This method invokes the GetDHCPServerConfigurable action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *LANHostConfigManagement1) GetDHCPServerConfigurable(ctx context.Context) (*LANHostConfigManagement1GetDHCPServerConfigurableResponse, error) {
+	out, err := self.igd.Invoke(ctx, self.Service, "GetDHCPServerConfigurable", nil)
+	if err != nil {
+		return nil, fmt.Errorf("GetDHCPServerConfigurable: %w", err)
+	}
+	var response LANHostConfigManagement1GetDHCPServerConfigurableResponse
+	if value, ok := out["NewDHCPServerConfigurable"]; ok {
+		if response.NewDHCPServerConfigurable, err = parseBool(value); err != nil {
+			return nil, fmt.Errorf("GetDHCPServerConfigurable: NewDHCPServerConfigurable: %w", err)
+		}
+	}
+	return &response, nil
+}
+
+// The in-arguments of the SetDHCPRelay action.
+type LANHostConfigManagement1SetDHCPRelayRequest struct {
+	NewDHCPRelay bool // DHCPRelay
+}
+
+// This method invokes the SetDHCPRelay action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *LANHostConfigManagement1) SetDHCPRelay(ctx context.Context, request *LANHostConfigManagement1SetDHCPRelayRequest) error {
+	_, err := self.igd.Invoke(ctx, self.Service, "SetDHCPRelay", map[string]string{
+		"NewDHCPRelay": formatBool(request.NewDHCPRelay),
+	})
+	if err != nil {
+		return fmt.Errorf("SetDHCPRelay: %w", err)
+	}
+	return nil
+}
+
+// The out-arguments of the GetDHCPRelay action.
+type LANHostConfigManagement1GetDHCPRelayResponse struct {
+	NewDHCPRelay bool // DHCPRelay
+}
+
+// This method invokes the GetDHCPRelay action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *LANHostConfigManagement1) GetDHCPRelay(ctx context.Context) (*LANHostConfigManagement1GetDHCPRelayResponse, error) {
+	out, err := self.igd.Invoke(ctx, self.Service, "GetDHCPRelay", nil)
+	if err != nil {
+		return nil, fmt.Errorf("GetDHCPRelay: %w", err)
+	}
+	var response LANHostConfigManagement1GetDHCPRelayResponse
+	if value, ok := out["NewDHCPRelay"]; ok {
+		if response.NewDHCPRelay, err = parseBool(value); err != nil {
+			return nil, fmt.Errorf("GetDHCPRelay: NewDHCPRelay: %w", err)
+		}
+	}
+	return &response, nil
+}
+
+// The in-arguments of the SetSubnetMask action.
+type LANHostConfigManagement1SetSubnetMaskRequest struct {
+	NewSubnetMask string // SubnetMask
+}
+
+// This method invokes the SetSubnetMask action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *LANHostConfigManagement1) SetSubnetMask(ctx context.Context, request *LANHostConfigManagement1SetSubnetMaskRequest) error {
+	_, err := self.igd.Invoke(ctx, self.Service, "SetSubnetMask", map[string]string{
+		"NewSubnetMask": request.NewSubnetMask,
+	})
+	if err != nil {
+		return fmt.Errorf("SetSubnetMask: %w", err)
+	}
+	return nil
+}
+
+// The out-arguments of the GetSubnetMask action.
+type LANHostConfigManagement1GetSubnetMaskResponse struct {
+	NewSubnetMask string // SubnetMask
+}
+
+// This method invokes the GetSubnetMask action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *LANHostConfigManagement1) GetSubnetMask(ctx context.Context) (*LANHostConfigManagement1GetSubnetMaskResponse, error) {
+	out, err := self.igd.Invoke(ctx, self.Service, "GetSubnetMask", nil)
+	if err != nil {
+		return nil, fmt.Errorf("GetSubnetMask: %w", err)
+	}
+	var response LANHostConfigManagement1GetSubnetMaskResponse
+	response.NewSubnetMask = out["NewSubnetMask"]
+	return &response, nil
+}
+
+// The in-arguments of the SetIPRouter action.
+type LANHostConfigManagement1SetIPRouterRequest struct {
+	NewIPRouters string // IPRouters
+}
+
+// This method invokes the SetIPRouter action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *LANHostConfigManagement1) SetIPRouter(ctx context.Context, request *LANHostConfigManagement1SetIPRouterRequest) error {
+	_, err := self.igd.Invoke(ctx, self.Service, "SetIPRouter", map[string]string{
+		"NewIPRouters": request.NewIPRouters,
+	})
+	if err != nil {
+		return fmt.Errorf("SetIPRouter: %w", err)
+	}
+	return nil
+}
+
+// The in-arguments of the DeleteIPRouter action.
+type LANHostConfigManagement1DeleteIPRouterRequest struct {
+	NewIPRouters string // IPRouters
+}
+
+// This method invokes the DeleteIPRouter action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *LANHostConfigManagement1) DeleteIPRouter(ctx context.Context, request *LANHostConfigManagement1DeleteIPRouterRequest) error {
+	_, err := self.igd.Invoke(ctx, self.Service, "DeleteIPRouter", map[string]string{
+		"NewIPRouters": request.NewIPRouters,
+	})
+	if err != nil {
+		return fmt.Errorf("DeleteIPRouter: %w", err)
+	}
+	return nil
+}
+
+// The out-arguments of the GetIPRoutersList action.
+type LANHostConfigManagement1GetIPRoutersListResponse struct {
+	NewIPRouters string // IPRouters
+}
+
+// This method invokes the GetIPRoutersList action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *LANHostConfigManagement1) GetIPRoutersList(ctx context.Context) (*LANHostConfigManagement1GetIPRoutersListResponse, error) {
+	out, err := self.igd.Invoke(ctx, self.Service, "GetIPRoutersList", nil)
+	if err != nil {
+		return nil, fmt.Errorf("GetIPRoutersList: %w", err)
+	}
+	var response LANHostConfigManagement1GetIPRoutersListResponse
+	response.NewIPRouters = out["NewIPRouters"]
+	return &response, nil
+}
+
+// The in-arguments of the SetDomainName action.
+type LANHostConfigManagement1SetDomainNameRequest struct {
+	NewDomainName string // DomainName
+}
+
+// This method invokes the SetDomainName action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *LANHostConfigManagement1) SetDomainName(ctx context.Context, request *LANHostConfigManagement1SetDomainNameRequest) error {
+	_, err := self.igd.Invoke(ctx, self.Service, "SetDomainName", map[string]string{
+		"NewDomainName": request.NewDomainName,
+	})
+	if err != nil {
+		return fmt.Errorf("SetDomainName: %w", err)
+	}
+	return nil
+}
+
+// The out-arguments of the GetDomainName action.
+type LANHostConfigManagement1GetDomainNameResponse struct {
+	NewDomainName string // DomainName
+}
+
+// This method invokes the GetDomainName action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *LANHostConfigManagement1) GetDomainName(ctx context.Context) (*LANHostConfigManagement1GetDomainNameResponse, error) {
+	out, err := self.igd.Invoke(ctx, self.Service, "GetDomainName", nil)
+	if err != nil {
+		return nil, fmt.Errorf("GetDomainName: %w", err)
+	}
+	var response LANHostConfigManagement1GetDomainNameResponse
+	response.NewDomainName = out["NewDomainName"]
+	return &response, nil
+}
+
+// The in-arguments of the SetAddressRange action.
+type LANHostConfigManagement1SetAddressRangeRequest struct {
+	NewMinAddress string // MinAddress
+	NewMaxAddress string // MaxAddress
+}
+
+// This method invokes the SetAddressRange action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *LANHostConfigManagement1) SetAddressRange(ctx context.Context, request *LANHostConfigManagement1SetAddressRangeRequest) error {
+	_, err := self.igd.Invoke(ctx, self.Service, "SetAddressRange", map[string]string{
+		"NewMinAddress": request.NewMinAddress,
+		"NewMaxAddress": request.NewMaxAddress,
+	})
+	if err != nil {
+		return fmt.Errorf("SetAddressRange: %w", err)
+	}
+	return nil
+}
+
+// The out-arguments of the GetAddressRange action.
+type LANHostConfigManagement1GetAddressRangeResponse struct {
+	NewMinAddress string // MinAddress
+	NewMaxAddress string // MaxAddress
+}
+
+// This method invokes the GetAddressRange action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *LANHostConfigManagement1) GetAddressRange(ctx context.Context) (*LANHostConfigManagement1GetAddressRangeResponse, error) {
+	out, err := self.igd.Invoke(ctx, self.Service, "GetAddressRange", nil)
+	if err != nil {
+		return nil, fmt.Errorf("GetAddressRange: %w", err)
+	}
+	var response LANHostConfigManagement1GetAddressRangeResponse
+	response.NewMinAddress = out["NewMinAddress"]
+	response.NewMaxAddress = out["NewMaxAddress"]
+	return &response, nil
+}
+
+// The in-arguments of the SetReservedAddress action.
+type LANHostConfigManagement1SetReservedAddressRequest struct {
+	NewReservedAddresses string // ReservedAddresses
+}
+
+// This method invokes the SetReservedAddress action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *LANHostConfigManagement1) SetReservedAddress(ctx context.Context, request *LANHostConfigManagement1SetReservedAddressRequest) error {
+	_, err := self.igd.Invoke(ctx, self.Service, "SetReservedAddress", map[string]string{
+		"NewReservedAddresses": request.NewReservedAddresses,
+	})
+	if err != nil {
+		return fmt.Errorf("SetReservedAddress: %w", err)
+	}
+	return nil
+}
+
+// The in-arguments of the DeleteReservedAddress action.
+type LANHostConfigManagement1DeleteReservedAddressRequest struct {
+	NewReservedAddresses string // ReservedAddresses
+}
+
+// This method invokes the DeleteReservedAddress action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *LANHostConfigManagement1) DeleteReservedAddress(ctx context.Context, request *LANHostConfigManagement1DeleteReservedAddressRequest) error {
+	_, err := self.igd.Invoke(ctx, self.Service, "DeleteReservedAddress", map[string]string{
+		"NewReservedAddresses": request.NewReservedAddresses,
+	})
+	if err != nil {
+		return fmt.Errorf("DeleteReservedAddress: %w", err)
+	}
+	return nil
+}
+
+// The out-arguments of the GetReservedAddresses action.
+type LANHostConfigManagement1GetReservedAddressesResponse struct {
+	NewReservedAddresses string // ReservedAddresses
+}
+
+// This method invokes the GetReservedAddresses action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *LANHostConfigManagement1) GetReservedAddresses(ctx context.Context) (*LANHostConfigManagement1GetReservedAddressesResponse, error) {
+	out, err := self.igd.Invoke(ctx, self.Service, "GetReservedAddresses", nil)
+	if err != nil {
+		return nil, fmt.Errorf("GetReservedAddresses: %w", err)
+	}
+	var response LANHostConfigManagement1GetReservedAddressesResponse
+	response.NewReservedAddresses = out["NewReservedAddresses"]
+	return &response, nil
+}
+
+// The in-arguments of the SetDNSServer action.
+type LANHostConfigManagement1SetDNSServerRequest struct {
+	NewDNSServers string // DNSServers
+}
+
+// This method invokes the SetDNSServer action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *LANHostConfigManagement1) SetDNSServer(ctx context.Context, request *LANHostConfigManagement1SetDNSServerRequest) error {
+	_, err := self.igd.Invoke(ctx, self.Service, "SetDNSServer", map[string]string{
+		"NewDNSServers": request.NewDNSServers,
+	})
+	if err != nil {
+		return fmt.Errorf("SetDNSServer: %w", err)
+	}
+	return nil
+}
+
+// The in-arguments of the DeleteDNSServer action.
+type LANHostConfigManagement1DeleteDNSServerRequest struct {
+	NewDNSServers string // DNSServers
+}
+
+// This method invokes the DeleteDNSServer action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *LANHostConfigManagement1) DeleteDNSServer(ctx context.Context, request *LANHostConfigManagement1DeleteDNSServerRequest) error {
+	_, err := self.igd.Invoke(ctx, self.Service, "DeleteDNSServer", map[string]string{
+		"NewDNSServers": request.NewDNSServers,
+	})
+	if err != nil {
+		return fmt.Errorf("DeleteDNSServer: %w", err)
+	}
+	return nil
+}
+
+// The out-arguments of the GetDNSServers action.
+type LANHostConfigManagement1GetDNSServersResponse struct {
+	NewDNSServers string // DNSServers
+}
+
+// This method invokes the GetDNSServers action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *LANHostConfigManagement1) GetDNSServers(ctx context.Context) (*LANHostConfigManagement1GetDNSServersResponse, error) {
+	out, err := self.igd.Invoke(ctx, self.Service, "GetDNSServers", nil)
+	if err != nil {
+		return nil, fmt.Errorf("GetDNSServers: %w", err)
+	}
+	var response LANHostConfigManagement1GetDNSServersResponse
+	response.NewDNSServers = out["NewDNSServers"]
+	return &response, nil
+}
@@ -0,0 +1,64 @@
+// Code generated by upnpgen from scpd/Layer3Forwarding1.xml. DO NOT EDIT.
+
+package goupnp
+
+import (
+	"context"
+	"fmt"
+)
+
+// The type of the services Layer3Forwarding1 is a client for.
+const Layer3Forwarding1ServiceType = "urn:schemas-upnp-org:service:Layer3Forwarding:1"
+
+// This type is a client for a service of type Layer3Forwarding1ServiceType of an
+// IGD. Use IGD.Layer3Forwarding1Clients() to obtain one.
+type Layer3Forwarding1 struct {
+	igd     *IGD
+	Service *Service
+}
+
+// This method returns a client for every service of type
+// Layer3Forwarding1ServiceType of the IGD, in the order they appear in its
+// description.
+func (self *IGD) Layer3Forwarding1Clients() (ret []*Layer3Forwarding1) {
+	for _, service := range self.description.Device.FindServices(Layer3Forwarding1ServiceType) {
+		ret = append(ret, &Layer3Forwarding1{igd: self, Service: service})
+	}
+	return
+}
+
+// The in-arguments of the SetDefaultConnectionService action.
+type Layer3Forwarding1SetDefaultConnectionServiceRequest struct {
+	NewDefaultConnectionService string // DefaultConnectionService
+}
+
+// This method invokes the SetDefaultConnectionService action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *Layer3Forwarding1) SetDefaultConnectionService(ctx context.Context, request *Layer3Forwarding1SetDefaultConnectionServiceRequest) error {
+	_, err := self.igd.Invoke(ctx, self.Service, "SetDefaultConnectionService", map[string]string{
+		"NewDefaultConnectionService": request.NewDefaultConnectionService,
+	})
+	if err != nil {
+		return fmt.Errorf("SetDefaultConnectionService: %w", err)
+	}
+	return nil
+}
+
+// The out-arguments of the GetDefaultConnectionService action.
+type Layer3Forwarding1GetDefaultConnectionServiceResponse struct {
+	NewDefaultConnectionService string // DefaultConnectionService
+}
+
+// This method invokes the GetDefaultConnectionService action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *Layer3Forwarding1) GetDefaultConnectionService(ctx context.Context) (*Layer3Forwarding1GetDefaultConnectionServiceResponse, error) {
+	out, err := self.igd.Invoke(ctx, self.Service, "GetDefaultConnectionService", nil)
+	if err != nil {
+		return nil, fmt.Errorf("GetDefaultConnectionService: %w", err)
+	}
+	var response Layer3Forwarding1GetDefaultConnectionServiceResponse
+	response.NewDefaultConnectionService = out["NewDefaultConnectionService"]
+	return &response, nil
+}
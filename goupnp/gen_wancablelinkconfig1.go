@@ -0,0 +1,230 @@
+// Code generated by upnpgen from scpd/WANCableLinkConfig1.xml. DO NOT EDIT.
+
+package goupnp
+
+import (
+	"context"
+	"fmt"
+)
+
+// The type of the services WANCableLinkConfig1 is a client for.
+const WANCableLinkConfig1ServiceType = "urn:schemas-upnp-org:service:WANCableLinkConfig:1"
+
+// This type is a client for a service of type WANCableLinkConfig1ServiceType of an
+// IGD. Use IGD.WANCableLinkConfig1Clients() to obtain one.
+type WANCableLinkConfig1 struct {
+	igd     *IGD
+	Service *Service
+}
+
+// This method returns a client for every service of type
+// WANCableLinkConfig1ServiceType of the IGD, in the order they appear in its
+// description.
+func (self *IGD) WANCableLinkConfig1Clients() (ret []*WANCableLinkConfig1) {
+	for _, service := range self.description.Device.FindServices(WANCableLinkConfig1ServiceType) {
+		ret = append(ret, &WANCableLinkConfig1{igd: self, Service: service})
+	}
+	return
+}
+
+// The out-arguments of the GetCableLinkConfigInfo action.
+type WANCableLinkConfig1GetCableLinkConfigInfoResponse struct {
+	NewCableLinkConfigState string // CableLinkConfigState
+	NewLinkType             string // LinkType
+}
+
+// This method invokes the GetCableLinkConfigInfo action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *WANCableLinkConfig1) GetCableLinkConfigInfo(ctx context.Context) (*WANCableLinkConfig1GetCableLinkConfigInfoResponse, error) {
+	out, err := self.igd.Invoke(ctx, self.Service, "GetCableLinkConfigInfo", nil)
+	if err != nil {
+		return nil, fmt.Errorf("GetCableLinkConfigInfo: %w", err)
+	}
+	var response WANCableLinkConfig1GetCableLinkConfigInfoResponse
+	response.NewCableLinkConfigState = out["NewCableLinkConfigState"]
+	response.NewLinkType = out["NewLinkType"]
+	return &response, nil
+}
+
+// The out-arguments of the GetDownstreamFrequency action.
+type WANCableLinkConfig1GetDownstreamFrequencyResponse struct {
+	NewDownstreamFrequency uint32 // DownstreamFrequency
+}
+
+// This method invokes the GetDownstreamFrequency action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *WANCableLinkConfig1) GetDownstreamFrequency(ctx context.Context) (*WANCableLinkConfig1GetDownstreamFrequencyResponse, error) {
+	out, err := self.igd.Invoke(ctx, self.Service, "GetDownstreamFrequency", nil)
+	if err != nil {
+		return nil, fmt.Errorf("GetDownstreamFrequency: %w", err)
+	}
+	var response WANCableLinkConfig1GetDownstreamFrequencyResponse
+	if value, ok := out["NewDownstreamFrequency"]; ok {
+		if response.NewDownstreamFrequency, err = parseUint[uint32](value, 32); err != nil {
+			return nil, fmt.Errorf("GetDownstreamFrequency: NewDownstreamFrequency: %w", err)
+		}
+	}
+	return &response, nil
+}
+
+// The out-arguments of the GetDownstreamModulation action.
+type WANCableLinkConfig1GetDownstreamModulationResponse struct {
+	NewDownstreamModulation string // DownstreamModulation
+}
+
+// This method invokes the GetDownstreamModulation action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *WANCableLinkConfig1) GetDownstreamModulation(ctx context.Context) (*WANCableLinkConfig1GetDownstreamModulationResponse, error) {
+	out, err := self.igd.Invoke(ctx, self.Service, "GetDownstreamModulation", nil)
+	if err != nil {
+		return nil, fmt.Errorf("GetDownstreamModulation: %w", err)
+	}
+	var response WANCableLinkConfig1GetDownstreamModulationResponse
+	response.NewDownstreamModulation = out["NewDownstreamModulation"]
+	return &response, nil
+}
+
+// The out-arguments of the GetUpstreamFrequency action.
+type WANCableLinkConfig1GetUpstreamFrequencyResponse struct {
+	NewUpstreamFrequency uint32 // UpstreamFrequency
+}
+
+// This method invokes the GetUpstreamFrequency action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *WANCableLinkConfig1) GetUpstreamFrequency(ctx context.Context) (*WANCableLinkConfig1GetUpstreamFrequencyResponse, error) {
+	out, err := self.igd.Invoke(ctx, self.Service, "GetUpstreamFrequency", nil)
+	if err != nil {
+		return nil, fmt.Errorf("GetUpstreamFrequency: %w", err)
+	}
+	var response WANCableLinkConfig1GetUpstreamFrequencyResponse
+	if value, ok := out["NewUpstreamFrequency"]; ok {
+		if response.NewUpstreamFrequency, err = parseUint[uint32](value, 32); err != nil {
+			return nil, fmt.Errorf("GetUpstreamFrequency: NewUpstreamFrequency: %w", err)
+		}
+	}
+	return &response, nil
+}
+
+// The out-arguments of the GetUpstreamModulation action.
+type WANCableLinkConfig1GetUpstreamModulationResponse struct {
+	NewUpstreamModulation string // UpstreamModulation
+}
+
+// This method invokes the GetUpstreamModulation action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *WANCableLinkConfig1) GetUpstreamModulation(ctx context.Context) (*WANCableLinkConfig1GetUpstreamModulationResponse, error) {
+	out, err := self.igd.Invoke(ctx, self.Service, "GetUpstreamModulation", nil)
+	if err != nil {
+		return nil, fmt.Errorf("GetUpstreamModulation: %w", err)
+	}
+	var response WANCableLinkConfig1GetUpstreamModulationResponse
+	response.NewUpstreamModulation = out["NewUpstreamModulation"]
+	return &response, nil
+}
+
+// The out-arguments of the GetUpstreamChannelID action.
+type WANCableLinkConfig1GetUpstreamChannelIDResponse struct {
+	NewUpstreamChannelID uint32 // UpstreamChannelID
+}
+
+// This method invokes the GetUpstreamChannelID action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *WANCableLinkConfig1) GetUpstreamChannelID(ctx context.Context) (*WANCableLinkConfig1GetUpstreamChannelIDResponse, error) {
+	out, err := self.igd.Invoke(ctx, self.Service, "GetUpstreamChannelID", nil)
+	if err != nil {
+		return nil, fmt.Errorf("GetUpstreamChannelID: %w", err)
+	}
+	var response WANCableLinkConfig1GetUpstreamChannelIDResponse
+	if value, ok := out["NewUpstreamChannelID"]; ok {
+		if response.NewUpstreamChannelID, err = parseUint[uint32](value, 32); err != nil {
+			return nil, fmt.Errorf("GetUpstreamChannelID: NewUpstreamChannelID: %w", err)
+		}
+	}
+	return &response, nil
+}
+
+// The out-arguments of the GetUpstreamPowerLevel action.
+type WANCableLinkConfig1GetUpstreamPowerLevelResponse struct {
+	NewUpstreamPowerLevel uint32 // UpstreamPowerLevel
+}
+
+// This method invokes the GetUpstreamPowerLevel action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *WANCableLinkConfig1) GetUpstreamPowerLevel(ctx context.Context) (*WANCableLinkConfig1GetUpstreamPowerLevelResponse, error) {
+	out, err := self.igd.Invoke(ctx, self.Service, "GetUpstreamPowerLevel", nil)
+	if err != nil {
+		return nil, fmt.Errorf("GetUpstreamPowerLevel: %w", err)
+	}
+	var response WANCableLinkConfig1GetUpstreamPowerLevelResponse
+	if value, ok := out["NewUpstreamPowerLevel"]; ok {
+		if response.NewUpstreamPowerLevel, err = parseUint[uint32](value, 32); err != nil {
+			return nil, fmt.Errorf("GetUpstreamPowerLevel: NewUpstreamPowerLevel: %w", err)
+		}
+	}
+	return &response, nil
+}
+
+// The out-arguments of the GetBPIEncryptionEnabled action.
+type WANCableLinkConfig1GetBPIEncryptionEnabledResponse struct {
+	NewBPIEncryptionEnabled bool // BPIEncryptionEnabled
+}
+
+// This method invokes the GetBPIEncryptionEnabled action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *WANCableLinkConfig1) GetBPIEncryptionEnabled(ctx context.Context) (*WANCableLinkConfig1GetBPIEncryptionEnabledResponse, error) {
+	out, err := self.igd.Invoke(ctx, self.Service, "GetBPIEncryptionEnabled", nil)
+	if err != nil {
+		return nil, fmt.Errorf("GetBPIEncryptionEnabled: %w", err)
+	}
+	var response WANCableLinkConfig1GetBPIEncryptionEnabledResponse
+	if value, ok := out["NewBPIEncryptionEnabled"]; ok {
+		if response.NewBPIEncryptionEnabled, err = parseBool(value); err != nil {
+			return nil, fmt.Errorf("GetBPIEncryptionEnabled: NewBPIEncryptionEnabled: %w", err)
+		}
+	}
+	return &response, nil
+}
+
+// The out-arguments of the GetConfigFile action.
+type WANCableLinkConfig1GetConfigFileResponse struct {
+	NewConfigFile string // ConfigFile
+}
+
+// This method invokes the GetConfigFile action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *WANCableLinkConfig1) GetConfigFile(ctx context.Context) (*WANCableLinkConfig1GetConfigFileResponse, error) {
+	out, err := self.igd.Invoke(ctx, self.Service, "GetConfigFile", nil)
+	if err != nil {
+		return nil, fmt.Errorf("GetConfigFile: %w", err)
+	}
+	var response WANCableLinkConfig1GetConfigFileResponse
+	response.NewConfigFile = out["NewConfigFile"]
+	return &response, nil
+}
+
+// The out-arguments of the GetTFTPServer action.
+type WANCableLinkConfig1GetTFTPServerResponse struct {
+	NewTFTPServer string // TFTPServer
+}
+
+// This method invokes the GetTFTPServer action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *WANCableLinkConfig1) GetTFTPServer(ctx context.Context) (*WANCableLinkConfig1GetTFTPServerResponse, error) {
+	out, err := self.igd.Invoke(ctx, self.Service, "GetTFTPServer", nil)
+	if err != nil {
+		return nil, fmt.Errorf("GetTFTPServer: %w", err)
+	}
+	var response WANCableLinkConfig1GetTFTPServerResponse
+	response.NewTFTPServer = out["NewTFTPServer"]
+	return &response, nil
+}
@@ -0,0 +1,255 @@
+// Code generated by upnpgen from scpd/WANCommonInterfaceConfig1.xml. DO NOT EDIT.
+
+package goupnp
+
+import (
+	"context"
+	"fmt"
+)
+
+// The type of the services WANCommonInterfaceConfig1 is a client for.
+const WANCommonInterfaceConfig1ServiceType = "urn:schemas-upnp-org:service:WANCommonInterfaceConfig:1"
+
+// This type is a client for a service of type WANCommonInterfaceConfig1ServiceType of an
+// IGD. Use IGD.WANCommonInterfaceConfig1Clients() to obtain one.
+type WANCommonInterfaceConfig1 struct {
+	igd     *IGD
+	Service *Service
+}
+
+// This method returns a client for every service of type
+// WANCommonInterfaceConfig1ServiceType of the IGD, in the order they appear in its
+// description.
+func (self *IGD) WANCommonInterfaceConfig1Clients() (ret []*WANCommonInterfaceConfig1) {
+	for _, service := range self.description.Device.FindServices(WANCommonInterfaceConfig1ServiceType) {
+		ret = append(ret, &WANCommonInterfaceConfig1{igd: self, Service: service})
+	}
+	return
+}
+
+// The in-arguments of the SetEnabledForInternet action.
+type WANCommonInterfaceConfig1SetEnabledForInternetRequest struct {
+	NewEnabledForInternet bool // EnabledForInternet
+}
+
+// This method invokes the SetEnabledForInternet action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *WANCommonInterfaceConfig1) SetEnabledForInternet(ctx context.Context, request *WANCommonInterfaceConfig1SetEnabledForInternetRequest) error {
+	_, err := self.igd.Invoke(ctx, self.Service, "SetEnabledForInternet", map[string]string{
+		"NewEnabledForInternet": formatBool(request.NewEnabledForInternet),
+	})
+	if err != nil {
+		return fmt.Errorf("SetEnabledForInternet: %w", err)
+	}
+	return nil
+}
+
+// The out-arguments of the GetEnabledForInternet action.
+type WANCommonInterfaceConfig1GetEnabledForInternetResponse struct {
+	NewEnabledForInternet bool // EnabledForInternet
+}
+
+// This method invokes the GetEnabledForInternet action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *WANCommonInterfaceConfig1) GetEnabledForInternet(ctx context.Context) (*WANCommonInterfaceConfig1GetEnabledForInternetResponse, error) {
+	out, err := self.igd.Invoke(ctx, self.Service, "GetEnabledForInternet", nil)
+	if err != nil {
+		return nil, fmt.Errorf("GetEnabledForInternet: %w", err)
+	}
+	var response WANCommonInterfaceConfig1GetEnabledForInternetResponse
+	if value, ok := out["NewEnabledForInternet"]; ok {
+		if response.NewEnabledForInternet, err = parseBool(value); err != nil {
+			return nil, fmt.Errorf("GetEnabledForInternet: NewEnabledForInternet: %w", err)
+		}
+	}
+	return &response, nil
+}
+
+// The out-arguments of the GetCommonLinkProperties action.
+type WANCommonInterfaceConfig1GetCommonLinkPropertiesResponse struct {
+	NewWANAccessType              string // WANAccessType
+	NewLayer1UpstreamMaxBitRate   uint32 // Layer1UpstreamMaxBitRate
+	NewLayer1DownstreamMaxBitRate uint32 // Layer1DownstreamMaxBitRate
+	NewPhysicalLinkStatus         string // PhysicalLinkStatus
+}
+
+// This method invokes the GetCommonLinkProperties action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *WANCommonInterfaceConfig1) GetCommonLinkProperties(ctx context.Context) (*WANCommonInterfaceConfig1GetCommonLinkPropertiesResponse, error) {
+	out, err := self.igd.Invoke(ctx, self.Service, "GetCommonLinkProperties", nil)
+	if err != nil {
+		return nil, fmt.Errorf("GetCommonLinkProperties: %w", err)
+	}
+	var response WANCommonInterfaceConfig1GetCommonLinkPropertiesResponse
+	response.NewWANAccessType = out["NewWANAccessType"]
+	if value, ok := out["NewLayer1UpstreamMaxBitRate"]; ok {
+		if response.NewLayer1UpstreamMaxBitRate, err = parseUint[uint32](value, 32); err != nil {
+			return nil, fmt.Errorf("GetCommonLinkProperties: NewLayer1UpstreamMaxBitRate: %w", err)
+		}
+	}
+	if value, ok := out["NewLayer1DownstreamMaxBitRate"]; ok {
+		if response.NewLayer1DownstreamMaxBitRate, err = parseUint[uint32](value, 32); err != nil {
+			return nil, fmt.Errorf("GetCommonLinkProperties: NewLayer1DownstreamMaxBitRate: %w", err)
+		}
+	}
+	response.NewPhysicalLinkStatus = out["NewPhysicalLinkStatus"]
+	return &response, nil
+}
+
+// The out-arguments of the GetWANAccessProvider action.
+type WANCommonInterfaceConfig1GetWANAccessProviderResponse struct {
+	NewWANAccessProvider string // WANAccessProvider
+}
+
+// This method invokes the GetWANAccessProvider action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *WANCommonInterfaceConfig1) GetWANAccessProvider(ctx context.Context) (*WANCommonInterfaceConfig1GetWANAccessProviderResponse, error) {
+	out, err := self.igd.Invoke(ctx, self.Service, "GetWANAccessProvider", nil)
+	if err != nil {
+		return nil, fmt.Errorf("GetWANAccessProvider: %w", err)
+	}
+	var response WANCommonInterfaceConfig1GetWANAccessProviderResponse
+	response.NewWANAccessProvider = out["NewWANAccessProvider"]
+	return &response, nil
+}
+
+// The out-arguments of the GetMaximumActiveConnections action.
+type WANCommonInterfaceConfig1GetMaximumActiveConnectionsResponse struct {
+	NewMaximumActiveConnections uint16 // MaximumActiveConnections
+}
+
+// This method invokes the GetMaximumActiveConnections action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *WANCommonInterfaceConfig1) GetMaximumActiveConnections(ctx context.Context) (*WANCommonInterfaceConfig1GetMaximumActiveConnectionsResponse, error) {
+	out, err := self.igd.Invoke(ctx, self.Service, "GetMaximumActiveConnections", nil)
+	if err != nil {
+		return nil, fmt.Errorf("GetMaximumActiveConnections: %w", err)
+	}
+	var response WANCommonInterfaceConfig1GetMaximumActiveConnectionsResponse
+	if value, ok := out["NewMaximumActiveConnections"]; ok {
+		if response.NewMaximumActiveConnections, err = parseUint[uint16](value, 16); err != nil {
+			return nil, fmt.Errorf("GetMaximumActiveConnections: NewMaximumActiveConnections: %w", err)
+		}
+	}
+	return &response, nil
+}
+
+// The out-arguments of the GetTotalBytesSent action.
+type WANCommonInterfaceConfig1GetTotalBytesSentResponse struct {
+	NewTotalBytesSent uint32 // TotalBytesSent
+}
+
+// This method invokes the GetTotalBytesSent action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *WANCommonInterfaceConfig1) GetTotalBytesSent(ctx context.Context) (*WANCommonInterfaceConfig1GetTotalBytesSentResponse, error) {
+	out, err := self.igd.Invoke(ctx, self.Service, "GetTotalBytesSent", nil)
+	if err != nil {
+		return nil, fmt.Errorf("GetTotalBytesSent: %w", err)
+	}
+	var response WANCommonInterfaceConfig1GetTotalBytesSentResponse
+	if value, ok := out["NewTotalBytesSent"]; ok {
+		if response.NewTotalBytesSent, err = parseUint[uint32](value, 32); err != nil {
+			return nil, fmt.Errorf("GetTotalBytesSent: NewTotalBytesSent: %w", err)
+		}
+	}
+	return &response, nil
+}
+
+// The out-arguments of the GetTotalBytesReceived action.
+type WANCommonInterfaceConfig1GetTotalBytesReceivedResponse struct {
+	NewTotalBytesReceived uint32 // TotalBytesReceived
+}
+
+// This method invokes the GetTotalBytesReceived action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *WANCommonInterfaceConfig1) GetTotalBytesReceived(ctx context.Context) (*WANCommonInterfaceConfig1GetTotalBytesReceivedResponse, error) {
+	out, err := self.igd.Invoke(ctx, self.Service, "GetTotalBytesReceived", nil)
+	if err != nil {
+		return nil, fmt.Errorf("GetTotalBytesReceived: %w", err)
+	}
+	var response WANCommonInterfaceConfig1GetTotalBytesReceivedResponse
+	if value, ok := out["NewTotalBytesReceived"]; ok {
+		if response.NewTotalBytesReceived, err = parseUint[uint32](value, 32); err != nil {
+			return nil, fmt.Errorf("GetTotalBytesReceived: NewTotalBytesReceived: %w", err)
+		}
+	}
+	return &response, nil
+}
+
+// The out-arguments of the GetTotalPacketsSent action.
+type WANCommonInterfaceConfig1GetTotalPacketsSentResponse struct {
+	NewTotalPacketsSent uint32 // TotalPacketsSent
+}
+
+// This method invokes the GetTotalPacketsSent action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *WANCommonInterfaceConfig1) GetTotalPacketsSent(ctx context.Context) (*WANCommonInterfaceConfig1GetTotalPacketsSentResponse, error) {
+	out, err := self.igd.Invoke(ctx, self.Service, "GetTotalPacketsSent", nil)
+	if err != nil {
+		return nil, fmt.Errorf("GetTotalPacketsSent: %w", err)
+	}
+	var response WANCommonInterfaceConfig1GetTotalPacketsSentResponse
+	if value, ok := out["NewTotalPacketsSent"]; ok {
+		if response.NewTotalPacketsSent, err = parseUint[uint32](value, 32); err != nil {
+			return nil, fmt.Errorf("GetTotalPacketsSent: NewTotalPacketsSent: %w", err)
+		}
+	}
+	return &response, nil
+}
+
+// The out-arguments of the GetTotalPacketsReceived action.
+type WANCommonInterfaceConfig1GetTotalPacketsReceivedResponse struct {
+	NewTotalPacketsReceived uint32 // TotalPacketsReceived
+}
+
+// This method invokes the GetTotalPacketsReceived action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *WANCommonInterfaceConfig1) GetTotalPacketsReceived(ctx context.Context) (*WANCommonInterfaceConfig1GetTotalPacketsReceivedResponse, error) {
+	out, err := self.igd.Invoke(ctx, self.Service, "GetTotalPacketsReceived", nil)
+	if err != nil {
+		return nil, fmt.Errorf("GetTotalPacketsReceived: %w", err)
+	}
+	var response WANCommonInterfaceConfig1GetTotalPacketsReceivedResponse
+	if value, ok := out["NewTotalPacketsReceived"]; ok {
+		if response.NewTotalPacketsReceived, err = parseUint[uint32](value, 32); err != nil {
+			return nil, fmt.Errorf("GetTotalPacketsReceived: NewTotalPacketsReceived: %w", err)
+		}
+	}
+	return &response, nil
+}
+
+// The in-arguments of the GetActiveConnection action.
+type WANCommonInterfaceConfig1GetActiveConnectionRequest struct {
+	NewActiveConnectionIndex uint16 // NumberOfActiveConnections
+}
+
+// The out-arguments of the GetActiveConnection action.
+type WANCommonInterfaceConfig1GetActiveConnectionResponse struct {
+	NewActiveConnDeviceContainer string // ActiveConnectionDeviceContainer
+	NewActiveConnectionServiceID string // ActiveConnectionServiceID
+}
+
+// This method invokes the GetActiveConnection action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *WANCommonInterfaceConfig1) GetActiveConnection(ctx context.Context, request *WANCommonInterfaceConfig1GetActiveConnectionRequest) (*WANCommonInterfaceConfig1GetActiveConnectionResponse, error) {
+	out, err := self.igd.Invoke(ctx, self.Service, "GetActiveConnection", map[string]string{
+		"NewActiveConnectionIndex": formatUint(uint64(request.NewActiveConnectionIndex)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("GetActiveConnection: %w", err)
+	}
+	var response WANCommonInterfaceConfig1GetActiveConnectionResponse
+	response.NewActiveConnDeviceContainer = out["NewActiveConnDeviceContainer"]
+	response.NewActiveConnectionServiceID = out["NewActiveConnectionServiceID"]
+	return &response, nil
+}
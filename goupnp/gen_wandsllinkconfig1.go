@@ -0,0 +1,218 @@
+// Code generated by upnpgen from scpd/WANDSLLinkConfig1.xml. DO NOT EDIT.
+
+package goupnp
+
+import (
+	"context"
+	"fmt"
+)
+
+// The type of the services WANDSLLinkConfig1 is a client for.
+const WANDSLLinkConfig1ServiceType = "urn:schemas-upnp-org:service:WANDSLLinkConfig:1"
+
+// This type is a client for a service of type WANDSLLinkConfig1ServiceType of an
+// IGD. Use IGD.WANDSLLinkConfig1Clients() to obtain one.
+type WANDSLLinkConfig1 struct {
+	igd     *IGD
+	Service *Service
+}
+
+// This method returns a client for every service of type
+// WANDSLLinkConfig1ServiceType of the IGD, in the order they appear in its
+// description.
+func (self *IGD) WANDSLLinkConfig1Clients() (ret []*WANDSLLinkConfig1) {
+	for _, service := range self.description.Device.FindServices(WANDSLLinkConfig1ServiceType) {
+		ret = append(ret, &WANDSLLinkConfig1{igd: self, Service: service})
+	}
+	return
+}
+
+// The in-arguments of the SetDSLLinkType action.
+type WANDSLLinkConfig1SetDSLLinkTypeRequest struct {
+	NewLinkType string // LinkType
+}
+
+// This method invokes the SetDSLLinkType action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *WANDSLLinkConfig1) SetDSLLinkType(ctx context.Context, request *WANDSLLinkConfig1SetDSLLinkTypeRequest) error {
+	_, err := self.igd.Invoke(ctx, self.Service, "SetDSLLinkType", map[string]string{
+		"NewLinkType": request.NewLinkType,
+	})
+	if err != nil {
+		return fmt.Errorf("SetDSLLinkType: %w", err)
+	}
+	return nil
+}
+
+// The out-arguments of the GetDSLLinkInfo action.
+type WANDSLLinkConfig1GetDSLLinkInfoResponse struct {
+	NewLinkType   string // LinkType
+	NewLinkStatus string // LinkStatus
+}
+
+// This method invokes the GetDSLLinkInfo action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *WANDSLLinkConfig1) GetDSLLinkInfo(ctx context.Context) (*WANDSLLinkConfig1GetDSLLinkInfoResponse, error) {
+	out, err := self.igd.Invoke(ctx, self.Service, "GetDSLLinkInfo", nil)
+	if err != nil {
+		return nil, fmt.Errorf("GetDSLLinkInfo: %w", err)
+	}
+	var response WANDSLLinkConfig1GetDSLLinkInfoResponse
+	response.NewLinkType = out["NewLinkType"]
+	response.NewLinkStatus = out["NewLinkStatus"]
+	return &response, nil
+}
+
+// The out-arguments of the GetAutoConfig action.
+type WANDSLLinkConfig1GetAutoConfigResponse struct {
+	NewAutoConfig bool // AutoConfig
+}
+
+// This method invokes the GetAutoConfig action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *WANDSLLinkConfig1) GetAutoConfig(ctx context.Context) (*WANDSLLinkConfig1GetAutoConfigResponse, error) {
+	out, err := self.igd.Invoke(ctx, self.Service, "GetAutoConfig", nil)
+	if err != nil {
+		return nil, fmt.Errorf("GetAutoConfig: %w", err)
+	}
+	var response WANDSLLinkConfig1GetAutoConfigResponse
+	if value, ok := out["NewAutoConfig"]; ok {
+		if response.NewAutoConfig, err = parseBool(value); err != nil {
+			return nil, fmt.Errorf("GetAutoConfig: NewAutoConfig: %w", err)
+		}
+	}
+	return &response, nil
+}
+
+// The out-arguments of the GetModulationType action.
+type WANDSLLinkConfig1GetModulationTypeResponse struct {
+	NewModulationType string // ModulationType
+}
+
+// This method invokes the GetModulationType action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *WANDSLLinkConfig1) GetModulationType(ctx context.Context) (*WANDSLLinkConfig1GetModulationTypeResponse, error) {
+	out, err := self.igd.Invoke(ctx, self.Service, "GetModulationType", nil)
+	if err != nil {
+		return nil, fmt.Errorf("GetModulationType: %w", err)
+	}
+	var response WANDSLLinkConfig1GetModulationTypeResponse
+	response.NewModulationType = out["NewModulationType"]
+	return &response, nil
+}
+
+// The in-arguments of the SetDestinationAddress action.
+type WANDSLLinkConfig1SetDestinationAddressRequest struct {
+	NewDestinationAddress string // DestinationAddress
+}
+
+// This method invokes the SetDestinationAddress action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *WANDSLLinkConfig1) SetDestinationAddress(ctx context.Context, request *WANDSLLinkConfig1SetDestinationAddressRequest) error {
+	_, err := self.igd.Invoke(ctx, self.Service, "SetDestinationAddress", map[string]string{
+		"NewDestinationAddress": request.NewDestinationAddress,
+	})
+	if err != nil {
+		return fmt.Errorf("SetDestinationAddress: %w", err)
+	}
+	return nil
+}
+
+// The out-arguments of the GetDestinationAddress action.
+type WANDSLLinkConfig1GetDestinationAddressResponse struct {
+	NewDestinationAddress string // DestinationAddress
+}
+
+// This method invokes the GetDestinationAddress action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *WANDSLLinkConfig1) GetDestinationAddress(ctx context.Context) (*WANDSLLinkConfig1GetDestinationAddressResponse, error) {
+	out, err := self.igd.Invoke(ctx, self.Service, "GetDestinationAddress", nil)
+	if err != nil {
+		return nil, fmt.Errorf("GetDestinationAddress: %w", err)
+	}
+	var response WANDSLLinkConfig1GetDestinationAddressResponse
+	response.NewDestinationAddress = out["NewDestinationAddress"]
+	return &response, nil
+}
+
+// The in-arguments of the SetATMEncapsulation action.
+type WANDSLLinkConfig1SetATMEncapsulationRequest struct {
+	NewATMEncapsulation string // ATMEncapsulation
+}
+
+// This method invokes the SetATMEncapsulation action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *WANDSLLinkConfig1) SetATMEncapsulation(ctx context.Context, request *WANDSLLinkConfig1SetATMEncapsulationRequest) error {
+	_, err := self.igd.Invoke(ctx, self.Service, "SetATMEncapsulation", map[string]string{
+		"NewATMEncapsulation": request.NewATMEncapsulation,
+	})
+	if err != nil {
+		return fmt.Errorf("SetATMEncapsulation: %w", err)
+	}
+	return nil
+}
+
+// The out-arguments of the GetATMEncapsulation action.
+type WANDSLLinkConfig1GetATMEncapsulationResponse struct {
+	NewATMEncapsulation string // ATMEncapsulation
+}
+
+// This method invokes the GetATMEncapsulation action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *WANDSLLinkConfig1) GetATMEncapsulation(ctx context.Context) (*WANDSLLinkConfig1GetATMEncapsulationResponse, error) {
+	out, err := self.igd.Invoke(ctx, self.Service, "GetATMEncapsulation", nil)
+	if err != nil {
+		return nil, fmt.Errorf("GetATMEncapsulation: %w", err)
+	}
+	var response WANDSLLinkConfig1GetATMEncapsulationResponse
+	response.NewATMEncapsulation = out["NewATMEncapsulation"]
+	return &response, nil
+}
+
+// The in-arguments of the SetFCSPreserved action.
+type WANDSLLinkConfig1SetFCSPreservedRequest struct {
+	NewFCSPreserved bool // FCSPreserved
+}
+
+// This method invokes the SetFCSPreserved action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *WANDSLLinkConfig1) SetFCSPreserved(ctx context.Context, request *WANDSLLinkConfig1SetFCSPreservedRequest) error {
+	_, err := self.igd.Invoke(ctx, self.Service, "SetFCSPreserved", map[string]string{
+		"NewFCSPreserved": formatBool(request.NewFCSPreserved),
+	})
+	if err != nil {
+		return fmt.Errorf("SetFCSPreserved: %w", err)
+	}
+	return nil
+}
+
+// The out-arguments of the GetFCSPreserved action.
+type WANDSLLinkConfig1GetFCSPreservedResponse struct {
+	NewFCSPreserved bool // FCSPreserved
+}
+
+// This method invokes the GetFCSPreserved action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *WANDSLLinkConfig1) GetFCSPreserved(ctx context.Context) (*WANDSLLinkConfig1GetFCSPreservedResponse, error) {
+	out, err := self.igd.Invoke(ctx, self.Service, "GetFCSPreserved", nil)
+	if err != nil {
+		return nil, fmt.Errorf("GetFCSPreserved: %w", err)
+	}
+	var response WANDSLLinkConfig1GetFCSPreservedResponse
+	if value, ok := out["NewFCSPreserved"]; ok {
+		if response.NewFCSPreserved, err = parseBool(value); err != nil {
+			return nil, fmt.Errorf("GetFCSPreserved: NewFCSPreserved: %w", err)
+		}
+	}
+	return &response, nil
+}
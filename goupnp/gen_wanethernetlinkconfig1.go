@@ -0,0 +1,46 @@
+// Code generated by upnpgen from scpd/WANEthernetLinkConfig1.xml. DO NOT EDIT.
+
+package goupnp
+
+import (
+	"context"
+	"fmt"
+)
+
+// The type of the services WANEthernetLinkConfig1 is a client for.
+const WANEthernetLinkConfig1ServiceType = "urn:schemas-upnp-org:service:WANEthernetLinkConfig:1"
+
+// This type is a client for a service of type WANEthernetLinkConfig1ServiceType of an
+// IGD. Use IGD.WANEthernetLinkConfig1Clients() to obtain one.
+type WANEthernetLinkConfig1 struct {
+	igd     *IGD
+	Service *Service
+}
+
+// This method returns a client for every service of type
+// WANEthernetLinkConfig1ServiceType of the IGD, in the order they appear in its
+// description.
+func (self *IGD) WANEthernetLinkConfig1Clients() (ret []*WANEthernetLinkConfig1) {
+	for _, service := range self.description.Device.FindServices(WANEthernetLinkConfig1ServiceType) {
+		ret = append(ret, &WANEthernetLinkConfig1{igd: self, Service: service})
+	}
+	return
+}
+
+// The out-arguments of the GetEthernetLinkStatus action.
+type WANEthernetLinkConfig1GetEthernetLinkStatusResponse struct {
+	NewEthernetLinkStatus string // EthernetLinkStatus
+}
+
+// This method invokes the GetEthernetLinkStatus action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *WANEthernetLinkConfig1) GetEthernetLinkStatus(ctx context.Context) (*WANEthernetLinkConfig1GetEthernetLinkStatusResponse, error) {
+	out, err := self.igd.Invoke(ctx, self.Service, "GetEthernetLinkStatus", nil)
+	if err != nil {
+		return nil, fmt.Errorf("GetEthernetLinkStatus: %w", err)
+	}
+	var response WANEthernetLinkConfig1GetEthernetLinkStatusResponse
+	response.NewEthernetLinkStatus = out["NewEthernetLinkStatus"]
+	return &response, nil
+}
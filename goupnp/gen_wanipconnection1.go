@@ -0,0 +1,449 @@
+// Code generated by upnpgen from scpd/WANIPConnection1.xml. DO NOT EDIT.
+
+package goupnp
+
+import (
+	"context"
+	"fmt"
+)
+
+// The type of the services WANIPConnection1 is a client for.
+const WANIPConnection1ServiceType = "urn:schemas-upnp-org:service:WANIPConnection:1"
+
+// This type is a client for a service of type WANIPConnection1ServiceType of an
+// IGD. Use IGD.WANIPConnection1Clients() to obtain one.
+type WANIPConnection1 struct {
+	igd     *IGD
+	Service *Service
+}
+
+// This method returns a client for every service of type
+// WANIPConnection1ServiceType of the IGD, in the order they appear in its
+// description.
+func (self *IGD) WANIPConnection1Clients() (ret []*WANIPConnection1) {
+	for _, service := range self.description.Device.FindServices(WANIPConnection1ServiceType) {
+		ret = append(ret, &WANIPConnection1{igd: self, Service: service})
+	}
+	return
+}
+
+// The in-arguments of the SetConnectionType action.
+type WANIPConnection1SetConnectionTypeRequest struct {
+	NewConnectionType string // ConnectionType
+}
+
+// This method invokes the SetConnectionType action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *WANIPConnection1) SetConnectionType(ctx context.Context, request *WANIPConnection1SetConnectionTypeRequest) error {
+	_, err := self.igd.Invoke(ctx, self.Service, "SetConnectionType", map[string]string{
+		"NewConnectionType": request.NewConnectionType,
+	})
+	if err != nil {
+		return fmt.Errorf("SetConnectionType: %w", err)
+	}
+	return nil
+}
+
+// The out-arguments of the GetConnectionTypeInfo action.
+type WANIPConnection1GetConnectionTypeInfoResponse struct {
+	NewConnectionType          string // ConnectionType
+	NewPossibleConnectionTypes string // PossibleConnectionTypes
+}
+
+// This method invokes the GetConnectionTypeInfo action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *WANIPConnection1) GetConnectionTypeInfo(ctx context.Context) (*WANIPConnection1GetConnectionTypeInfoResponse, error) {
+	out, err := self.igd.Invoke(ctx, self.Service, "GetConnectionTypeInfo", nil)
+	if err != nil {
+		return nil, fmt.Errorf("GetConnectionTypeInfo: %w", err)
+	}
+	var response WANIPConnection1GetConnectionTypeInfoResponse
+	response.NewConnectionType = out["NewConnectionType"]
+	response.NewPossibleConnectionTypes = out["NewPossibleConnectionTypes"]
+	return &response, nil
+}
+
+// This method invokes the RequestConnection action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *WANIPConnection1) RequestConnection(ctx context.Context) error {
+	_, err := self.igd.Invoke(ctx, self.Service, "RequestConnection", nil)
+	if err != nil {
+		return fmt.Errorf("RequestConnection: %w", err)
+	}
+	return nil
+}
+
+// This method invokes the RequestTermination action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *WANIPConnection1) RequestTermination(ctx context.Context) error {
+	_, err := self.igd.Invoke(ctx, self.Service, "RequestTermination", nil)
+	if err != nil {
+		return fmt.Errorf("RequestTermination: %w", err)
+	}
+	return nil
+}
+
+// This method invokes the ForceTermination action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *WANIPConnection1) ForceTermination(ctx context.Context) error {
+	_, err := self.igd.Invoke(ctx, self.Service, "ForceTermination", nil)
+	if err != nil {
+		return fmt.Errorf("ForceTermination: %w", err)
+	}
+	return nil
+}
+
+// The in-arguments of the SetAutoDisconnectTime action.
+type WANIPConnection1SetAutoDisconnectTimeRequest struct {
+	NewAutoDisconnectTime uint32 // AutoDisconnectTime
+}
+
+// This method invokes the SetAutoDisconnectTime action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *WANIPConnection1) SetAutoDisconnectTime(ctx context.Context, request *WANIPConnection1SetAutoDisconnectTimeRequest) error {
+	_, err := self.igd.Invoke(ctx, self.Service, "SetAutoDisconnectTime", map[string]string{
+		"NewAutoDisconnectTime": formatUint(uint64(request.NewAutoDisconnectTime)),
+	})
+	if err != nil {
+		return fmt.Errorf("SetAutoDisconnectTime: %w", err)
+	}
+	return nil
+}
+
+// The in-arguments of the SetIdleDisconnectTime action.
+type WANIPConnection1SetIdleDisconnectTimeRequest struct {
+	NewIdleDisconnectTime uint32 // IdleDisconnectTime
+}
+
+// This method invokes the SetIdleDisconnectTime action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *WANIPConnection1) SetIdleDisconnectTime(ctx context.Context, request *WANIPConnection1SetIdleDisconnectTimeRequest) error {
+	_, err := self.igd.Invoke(ctx, self.Service, "SetIdleDisconnectTime", map[string]string{
+		"NewIdleDisconnectTime": formatUint(uint64(request.NewIdleDisconnectTime)),
+	})
+	if err != nil {
+		return fmt.Errorf("SetIdleDisconnectTime: %w", err)
+	}
+	return nil
+}
+
+// The in-arguments of the SetWarnDisconnectDelay action.
+type WANIPConnection1SetWarnDisconnectDelayRequest struct {
+	NewWarnDisconnectDelay uint32 // WarnDisconnectDelay
+}
+
+// This method invokes the SetWarnDisconnectDelay action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *WANIPConnection1) SetWarnDisconnectDelay(ctx context.Context, request *WANIPConnection1SetWarnDisconnectDelayRequest) error {
+	_, err := self.igd.Invoke(ctx, self.Service, "SetWarnDisconnectDelay", map[string]string{
+		"NewWarnDisconnectDelay": formatUint(uint64(request.NewWarnDisconnectDelay)),
+	})
+	if err != nil {
+		return fmt.Errorf("SetWarnDisconnectDelay: %w", err)
+	}
+	return nil
+}
+
+// The out-arguments of the GetStatusInfo action.
+type WANIPConnection1GetStatusInfoResponse struct {
+	NewConnectionStatus    string // ConnectionStatus
+	NewLastConnectionError string // LastConnectionError
+	NewUptime              uint32 // Uptime
+}
+
+// This method invokes the GetStatusInfo action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *WANIPConnection1) GetStatusInfo(ctx context.Context) (*WANIPConnection1GetStatusInfoResponse, error) {
+	out, err := self.igd.Invoke(ctx, self.Service, "GetStatusInfo", nil)
+	if err != nil {
+		return nil, fmt.Errorf("GetStatusInfo: %w", err)
+	}
+	var response WANIPConnection1GetStatusInfoResponse
+	response.NewConnectionStatus = out["NewConnectionStatus"]
+	response.NewLastConnectionError = out["NewLastConnectionError"]
+	if value, ok := out["NewUptime"]; ok {
+		if response.NewUptime, err = parseUint[uint32](value, 32); err != nil {
+			return nil, fmt.Errorf("GetStatusInfo: NewUptime: %w", err)
+		}
+	}
+	return &response, nil
+}
+
+// The out-arguments of the GetAutoDisconnectTime action.
+type WANIPConnection1GetAutoDisconnectTimeResponse struct {
+	NewAutoDisconnectTime uint32 // AutoDisconnectTime
+}
+
+// This method invokes the GetAutoDisconnectTime action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *WANIPConnection1) GetAutoDisconnectTime(ctx context.Context) (*WANIPConnection1GetAutoDisconnectTimeResponse, error) {
+	out, err := self.igd.Invoke(ctx, self.Service, "GetAutoDisconnectTime", nil)
+	if err != nil {
+		return nil, fmt.Errorf("GetAutoDisconnectTime: %w", err)
+	}
+	var response WANIPConnection1GetAutoDisconnectTimeResponse
+	if value, ok := out["NewAutoDisconnectTime"]; ok {
+		if response.NewAutoDisconnectTime, err = parseUint[uint32](value, 32); err != nil {
+			return nil, fmt.Errorf("GetAutoDisconnectTime: NewAutoDisconnectTime: %w", err)
+		}
+	}
+	return &response, nil
+}
+
+// The out-arguments of the GetIdleDisconnectTime action.
+type WANIPConnection1GetIdleDisconnectTimeResponse struct {
+	NewIdleDisconnectTime uint32 // IdleDisconnectTime
+}
+
+// This method invokes the GetIdleDisconnectTime action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *WANIPConnection1) GetIdleDisconnectTime(ctx context.Context) (*WANIPConnection1GetIdleDisconnectTimeResponse, error) {
+	out, err := self.igd.Invoke(ctx, self.Service, "GetIdleDisconnectTime", nil)
+	if err != nil {
+		return nil, fmt.Errorf("GetIdleDisconnectTime: %w", err)
+	}
+	var response WANIPConnection1GetIdleDisconnectTimeResponse
+	if value, ok := out["NewIdleDisconnectTime"]; ok {
+		if response.NewIdleDisconnectTime, err = parseUint[uint32](value, 32); err != nil {
+			return nil, fmt.Errorf("GetIdleDisconnectTime: NewIdleDisconnectTime: %w", err)
+		}
+	}
+	return &response, nil
+}
+
+// The out-arguments of the GetWarnDisconnectDelay action.
+type WANIPConnection1GetWarnDisconnectDelayResponse struct {
+	NewWarnDisconnectDelay uint32 // WarnDisconnectDelay
+}
+
+// This method invokes the GetWarnDisconnectDelay action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *WANIPConnection1) GetWarnDisconnectDelay(ctx context.Context) (*WANIPConnection1GetWarnDisconnectDelayResponse, error) {
+	out, err := self.igd.Invoke(ctx, self.Service, "GetWarnDisconnectDelay", nil)
+	if err != nil {
+		return nil, fmt.Errorf("GetWarnDisconnectDelay: %w", err)
+	}
+	var response WANIPConnection1GetWarnDisconnectDelayResponse
+	if value, ok := out["NewWarnDisconnectDelay"]; ok {
+		if response.NewWarnDisconnectDelay, err = parseUint[uint32](value, 32); err != nil {
+			return nil, fmt.Errorf("GetWarnDisconnectDelay: NewWarnDisconnectDelay: %w", err)
+		}
+	}
+	return &response, nil
+}
+
+// The out-arguments of the GetNATRSIPStatus action.
+type WANIPConnection1GetNATRSIPStatusResponse struct {
+	NewRSIPAvailable bool // RSIPAvailable
+	NewNATEnabled    bool // NATEnabled
+}
+
+// This method invokes the GetNATRSIPStatus action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *WANIPConnection1) GetNATRSIPStatus(ctx context.Context) (*WANIPConnection1GetNATRSIPStatusResponse, error) {
+	out, err := self.igd.Invoke(ctx, self.Service, "GetNATRSIPStatus", nil)
+	if err != nil {
+		return nil, fmt.Errorf("GetNATRSIPStatus: %w", err)
+	}
+	var response WANIPConnection1GetNATRSIPStatusResponse
+	if value, ok := out["NewRSIPAvailable"]; ok {
+		if response.NewRSIPAvailable, err = parseBool(value); err != nil {
+			return nil, fmt.Errorf("GetNATRSIPStatus: NewRSIPAvailable: %w", err)
+		}
+	}
+	if value, ok := out["NewNATEnabled"]; ok {
+		if response.NewNATEnabled, err = parseBool(value); err != nil {
+			return nil, fmt.Errorf("GetNATRSIPStatus: NewNATEnabled: %w", err)
+		}
+	}
+	return &response, nil
+}
+
+// The in-arguments of the GetGenericPortMappingEntry action.
+type WANIPConnection1GetGenericPortMappingEntryRequest struct {
+	NewPortMappingIndex uint16 // PortMappingNumberOfEntries
+}
+
+// The out-arguments of the GetGenericPortMappingEntry action.
+type WANIPConnection1GetGenericPortMappingEntryResponse struct {
+	NewRemoteHost             string // RemoteHost
+	NewExternalPort           uint16 // ExternalPort
+	NewProtocol               string // PortMappingProtocol
+	NewInternalPort           uint16 // InternalPort
+	NewInternalClient         string // InternalClient
+	NewEnabled                bool   // PortMappingEnabled
+	NewPortMappingDescription string // PortMappingDescription
+	NewLeaseDuration          uint32 // PortMappingLeaseDuration
+}
+
+// This method invokes the GetGenericPortMappingEntry action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *WANIPConnection1) GetGenericPortMappingEntry(ctx context.Context, request *WANIPConnection1GetGenericPortMappingEntryRequest) (*WANIPConnection1GetGenericPortMappingEntryResponse, error) {
+	out, err := self.igd.Invoke(ctx, self.Service, "GetGenericPortMappingEntry", map[string]string{
+		"NewPortMappingIndex": formatUint(uint64(request.NewPortMappingIndex)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("GetGenericPortMappingEntry: %w", err)
+	}
+	var response WANIPConnection1GetGenericPortMappingEntryResponse
+	response.NewRemoteHost = out["NewRemoteHost"]
+	if value, ok := out["NewExternalPort"]; ok {
+		if response.NewExternalPort, err = parseUint[uint16](value, 16); err != nil {
+			return nil, fmt.Errorf("GetGenericPortMappingEntry: NewExternalPort: %w", err)
+		}
+	}
+	response.NewProtocol = out["NewProtocol"]
+	if value, ok := out["NewInternalPort"]; ok {
+		if response.NewInternalPort, err = parseUint[uint16](value, 16); err != nil {
+			return nil, fmt.Errorf("GetGenericPortMappingEntry: NewInternalPort: %w", err)
+		}
+	}
+	response.NewInternalClient = out["NewInternalClient"]
+	if value, ok := out["NewEnabled"]; ok {
+		if response.NewEnabled, err = parseBool(value); err != nil {
+			return nil, fmt.Errorf("GetGenericPortMappingEntry: NewEnabled: %w", err)
+		}
+	}
+	response.NewPortMappingDescription = out["NewPortMappingDescription"]
+	if value, ok := out["NewLeaseDuration"]; ok {
+		if response.NewLeaseDuration, err = parseUint[uint32](value, 32); err != nil {
+			return nil, fmt.Errorf("GetGenericPortMappingEntry: NewLeaseDuration: %w", err)
+		}
+	}
+	return &response, nil
+}
+
+// The in-arguments of the GetSpecificPortMappingEntry action.
+type WANIPConnection1GetSpecificPortMappingEntryRequest struct {
+	NewRemoteHost   string // RemoteHost
+	NewExternalPort uint16 // ExternalPort
+	NewProtocol     string // PortMappingProtocol
+}
+
+// The out-arguments of the GetSpecificPortMappingEntry action.
+type WANIPConnection1GetSpecificPortMappingEntryResponse struct {
+	NewInternalPort           uint16 // InternalPort
+	NewInternalClient         string // InternalClient
+	NewEnabled                bool   // PortMappingEnabled
+	NewPortMappingDescription string // PortMappingDescription
+	NewLeaseDuration          uint32 // PortMappingLeaseDuration
+}
+
+// This method invokes the GetSpecificPortMappingEntry action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *WANIPConnection1) GetSpecificPortMappingEntry(ctx context.Context, request *WANIPConnection1GetSpecificPortMappingEntryRequest) (*WANIPConnection1GetSpecificPortMappingEntryResponse, error) {
+	out, err := self.igd.Invoke(ctx, self.Service, "GetSpecificPortMappingEntry", map[string]string{
+		"NewRemoteHost":   request.NewRemoteHost,
+		"NewExternalPort": formatUint(uint64(request.NewExternalPort)),
+		"NewProtocol":     request.NewProtocol,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("GetSpecificPortMappingEntry: %w", err)
+	}
+	var response WANIPConnection1GetSpecificPortMappingEntryResponse
+	if value, ok := out["NewInternalPort"]; ok {
+		if response.NewInternalPort, err = parseUint[uint16](value, 16); err != nil {
+			return nil, fmt.Errorf("GetSpecificPortMappingEntry: NewInternalPort: %w", err)
+		}
+	}
+	response.NewInternalClient = out["NewInternalClient"]
+	if value, ok := out["NewEnabled"]; ok {
+		if response.NewEnabled, err = parseBool(value); err != nil {
+			return nil, fmt.Errorf("GetSpecificPortMappingEntry: NewEnabled: %w", err)
+		}
+	}
+	response.NewPortMappingDescription = out["NewPortMappingDescription"]
+	if value, ok := out["NewLeaseDuration"]; ok {
+		if response.NewLeaseDuration, err = parseUint[uint32](value, 32); err != nil {
+			return nil, fmt.Errorf("GetSpecificPortMappingEntry: NewLeaseDuration: %w", err)
+		}
+	}
+	return &response, nil
+}
+
+// The in-arguments of the AddPortMapping action.
+type WANIPConnection1AddPortMappingRequest struct {
+	NewRemoteHost             string // RemoteHost
+	NewExternalPort           uint16 // ExternalPort
+	NewProtocol               string // PortMappingProtocol
+	NewInternalPort           uint16 // InternalPort
+	NewInternalClient         string // InternalClient
+	NewEnabled                bool   // PortMappingEnabled
+	NewPortMappingDescription string // PortMappingDescription
+	NewLeaseDuration          uint32 // PortMappingLeaseDuration
+}
+
+// This method invokes the AddPortMapping action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *WANIPConnection1) AddPortMapping(ctx context.Context, request *WANIPConnection1AddPortMappingRequest) error {
+	_, err := self.igd.Invoke(ctx, self.Service, "AddPortMapping", map[string]string{
+		"NewRemoteHost":             request.NewRemoteHost,
+		"NewExternalPort":           formatUint(uint64(request.NewExternalPort)),
+		"NewProtocol":               request.NewProtocol,
+		"NewInternalPort":           formatUint(uint64(request.NewInternalPort)),
+		"NewInternalClient":         request.NewInternalClient,
+		"NewEnabled":                formatBool(request.NewEnabled),
+		"NewPortMappingDescription": request.NewPortMappingDescription,
+		"NewLeaseDuration":          formatUint(uint64(request.NewLeaseDuration)),
+	})
+	if err != nil {
+		return fmt.Errorf("AddPortMapping: %w", err)
+	}
+	return nil
+}
+
+// The in-arguments of the DeletePortMapping action.
+type WANIPConnection1DeletePortMappingRequest struct {
+	NewRemoteHost   string // RemoteHost
+	NewExternalPort uint16 // ExternalPort
+	NewProtocol     string // PortMappingProtocol
+}
+
+// This method invokes the DeletePortMapping action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *WANIPConnection1) DeletePortMapping(ctx context.Context, request *WANIPConnection1DeletePortMappingRequest) error {
+	_, err := self.igd.Invoke(ctx, self.Service, "DeletePortMapping", map[string]string{
+		"NewRemoteHost":   request.NewRemoteHost,
+		"NewExternalPort": formatUint(uint64(request.NewExternalPort)),
+		"NewProtocol":     request.NewProtocol,
+	})
+	if err != nil {
+		return fmt.Errorf("DeletePortMapping: %w", err)
+	}
+	return nil
+}
+
+// The out-arguments of the GetExternalIPAddress action.
+type WANIPConnection1GetExternalIPAddressResponse struct {
+	NewExternalIPAddress string // ExternalIPAddress
+}
+
+// This method invokes the GetExternalIPAddress action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *WANIPConnection1) GetExternalIPAddress(ctx context.Context) (*WANIPConnection1GetExternalIPAddressResponse, error) {
+	out, err := self.igd.Invoke(ctx, self.Service, "GetExternalIPAddress", nil)
+	if err != nil {
+		return nil, fmt.Errorf("GetExternalIPAddress: %w", err)
+	}
+	var response WANIPConnection1GetExternalIPAddressResponse
+	response.NewExternalIPAddress = out["NewExternalIPAddress"]
+	return &response, nil
+}
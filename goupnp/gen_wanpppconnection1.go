@@ -0,0 +1,587 @@
+// Code generated by upnpgen from scpd/WANPPPConnection1.xml. DO NOT EDIT.
+
+package goupnp
+
+import (
+	"context"
+	"fmt"
+)
+
+// The type of the services WANPPPConnection1 is a client for.
+const WANPPPConnection1ServiceType = "urn:schemas-upnp-org:service:WANPPPConnection:1"
+
+// This type is a client for a service of type WANPPPConnection1ServiceType of an
+// IGD. Use IGD.WANPPPConnection1Clients() to obtain one.
+type WANPPPConnection1 struct {
+	igd     *IGD
+	Service *Service
+}
+
+// This method returns a client for every service of type
+// WANPPPConnection1ServiceType of the IGD, in the order they appear in its
+// description.
+func (self *IGD) WANPPPConnection1Clients() (ret []*WANPPPConnection1) {
+	for _, service := range self.description.Device.FindServices(WANPPPConnection1ServiceType) {
+		ret = append(ret, &WANPPPConnection1{igd: self, Service: service})
+	}
+	return
+}
+
+// The in-arguments of the SetConnectionType action.
+type WANPPPConnection1SetConnectionTypeRequest struct {
+	NewConnectionType string // ConnectionType
+}
+
+// This method invokes the SetConnectionType action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *WANPPPConnection1) SetConnectionType(ctx context.Context, request *WANPPPConnection1SetConnectionTypeRequest) error {
+	_, err := self.igd.Invoke(ctx, self.Service, "SetConnectionType", map[string]string{
+		"NewConnectionType": request.NewConnectionType,
+	})
+	if err != nil {
+		return fmt.Errorf("SetConnectionType: %w", err)
+	}
+	return nil
+}
+
+// The out-arguments of the GetConnectionTypeInfo action.
+type WANPPPConnection1GetConnectionTypeInfoResponse struct {
+	NewConnectionType          string // ConnectionType
+	NewPossibleConnectionTypes string // PossibleConnectionTypes
+}
+
+// This method invokes the GetConnectionTypeInfo action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *WANPPPConnection1) GetConnectionTypeInfo(ctx context.Context) (*WANPPPConnection1GetConnectionTypeInfoResponse, error) {
+	out, err := self.igd.Invoke(ctx, self.Service, "GetConnectionTypeInfo", nil)
+	if err != nil {
+		return nil, fmt.Errorf("GetConnectionTypeInfo: %w", err)
+	}
+	var response WANPPPConnection1GetConnectionTypeInfoResponse
+	response.NewConnectionType = out["NewConnectionType"]
+	response.NewPossibleConnectionTypes = out["NewPossibleConnectionTypes"]
+	return &response, nil
+}
+
+// The in-arguments of the ConfigureConnection action.
+type WANPPPConnection1ConfigureConnectionRequest struct {
+	NewUserName string // UserName
+	NewPassword string // Password
+}
+
+// This method invokes the ConfigureConnection action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *WANPPPConnection1) ConfigureConnection(ctx context.Context, request *WANPPPConnection1ConfigureConnectionRequest) error {
+	_, err := self.igd.Invoke(ctx, self.Service, "ConfigureConnection", map[string]string{
+		"NewUserName": request.NewUserName,
+		"NewPassword": request.NewPassword,
+	})
+	if err != nil {
+		return fmt.Errorf("ConfigureConnection: %w", err)
+	}
+	return nil
+}
+
+// This method invokes the RequestConnection action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *WANPPPConnection1) RequestConnection(ctx context.Context) error {
+	_, err := self.igd.Invoke(ctx, self.Service, "RequestConnection", nil)
+	if err != nil {
+		return fmt.Errorf("RequestConnection: %w", err)
+	}
+	return nil
+}
+
+// This method invokes the RequestTermination action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *WANPPPConnection1) RequestTermination(ctx context.Context) error {
+	_, err := self.igd.Invoke(ctx, self.Service, "RequestTermination", nil)
+	if err != nil {
+		return fmt.Errorf("RequestTermination: %w", err)
+	}
+	return nil
+}
+
+// This method invokes the ForceTermination action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *WANPPPConnection1) ForceTermination(ctx context.Context) error {
+	_, err := self.igd.Invoke(ctx, self.Service, "ForceTermination", nil)
+	if err != nil {
+		return fmt.Errorf("ForceTermination: %w", err)
+	}
+	return nil
+}
+
+// The in-arguments of the SetAutoDisconnectTime action.
+type WANPPPConnection1SetAutoDisconnectTimeRequest struct {
+	NewAutoDisconnectTime uint32 // AutoDisconnectTime
+}
+
+// This method invokes the SetAutoDisconnectTime action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *WANPPPConnection1) SetAutoDisconnectTime(ctx context.Context, request *WANPPPConnection1SetAutoDisconnectTimeRequest) error {
+	_, err := self.igd.Invoke(ctx, self.Service, "SetAutoDisconnectTime", map[string]string{
+		"NewAutoDisconnectTime": formatUint(uint64(request.NewAutoDisconnectTime)),
+	})
+	if err != nil {
+		return fmt.Errorf("SetAutoDisconnectTime: %w", err)
+	}
+	return nil
+}
+
+// The in-arguments of the SetIdleDisconnectTime action.
+type WANPPPConnection1SetIdleDisconnectTimeRequest struct {
+	NewIdleDisconnectTime uint32 // IdleDisconnectTime
+}
+
+// This method invokes the SetIdleDisconnectTime action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *WANPPPConnection1) SetIdleDisconnectTime(ctx context.Context, request *WANPPPConnection1SetIdleDisconnectTimeRequest) error {
+	_, err := self.igd.Invoke(ctx, self.Service, "SetIdleDisconnectTime", map[string]string{
+		"NewIdleDisconnectTime": formatUint(uint64(request.NewIdleDisconnectTime)),
+	})
+	if err != nil {
+		return fmt.Errorf("SetIdleDisconnectTime: %w", err)
+	}
+	return nil
+}
+
+// The in-arguments of the SetWarnDisconnectDelay action.
+type WANPPPConnection1SetWarnDisconnectDelayRequest struct {
+	NewWarnDisconnectDelay uint32 // WarnDisconnectDelay
+}
+
+// This method invokes the SetWarnDisconnectDelay action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *WANPPPConnection1) SetWarnDisconnectDelay(ctx context.Context, request *WANPPPConnection1SetWarnDisconnectDelayRequest) error {
+	_, err := self.igd.Invoke(ctx, self.Service, "SetWarnDisconnectDelay", map[string]string{
+		"NewWarnDisconnectDelay": formatUint(uint64(request.NewWarnDisconnectDelay)),
+	})
+	if err != nil {
+		return fmt.Errorf("SetWarnDisconnectDelay: %w", err)
+	}
+	return nil
+}
+
+// The out-arguments of the GetStatusInfo action.
+type WANPPPConnection1GetStatusInfoResponse struct {
+	NewConnectionStatus    string // ConnectionStatus
+	NewLastConnectionError string // LastConnectionError
+	NewUptime              uint32 // Uptime
+}
+
+// This method invokes the GetStatusInfo action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *WANPPPConnection1) GetStatusInfo(ctx context.Context) (*WANPPPConnection1GetStatusInfoResponse, error) {
+	out, err := self.igd.Invoke(ctx, self.Service, "GetStatusInfo", nil)
+	if err != nil {
+		return nil, fmt.Errorf("GetStatusInfo: %w", err)
+	}
+	var response WANPPPConnection1GetStatusInfoResponse
+	response.NewConnectionStatus = out["NewConnectionStatus"]
+	response.NewLastConnectionError = out["NewLastConnectionError"]
+	if value, ok := out["NewUptime"]; ok {
+		if response.NewUptime, err = parseUint[uint32](value, 32); err != nil {
+			return nil, fmt.Errorf("GetStatusInfo: NewUptime: %w", err)
+		}
+	}
+	return &response, nil
+}
+
+// The out-arguments of the GetLinkLayerMaxBitRates action.
+type WANPPPConnection1GetLinkLayerMaxBitRatesResponse struct {
+	NewUpstreamMaxBitRate   uint32 // UpstreamMaxBitRate
+	NewDownstreamMaxBitRate uint32 // DownstreamMaxBitRate
+}
+
+// This method invokes the GetLinkLayerMaxBitRates action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *WANPPPConnection1) GetLinkLayerMaxBitRates(ctx context.Context) (*WANPPPConnection1GetLinkLayerMaxBitRatesResponse, error) {
+	out, err := self.igd.Invoke(ctx, self.Service, "GetLinkLayerMaxBitRates", nil)
+	if err != nil {
+		return nil, fmt.Errorf("GetLinkLayerMaxBitRates: %w", err)
+	}
+	var response WANPPPConnection1GetLinkLayerMaxBitRatesResponse
+	if value, ok := out["NewUpstreamMaxBitRate"]; ok {
+		if response.NewUpstreamMaxBitRate, err = parseUint[uint32](value, 32); err != nil {
+			return nil, fmt.Errorf("GetLinkLayerMaxBitRates: NewUpstreamMaxBitRate: %w", err)
+		}
+	}
+	if value, ok := out["NewDownstreamMaxBitRate"]; ok {
+		if response.NewDownstreamMaxBitRate, err = parseUint[uint32](value, 32); err != nil {
+			return nil, fmt.Errorf("GetLinkLayerMaxBitRates: NewDownstreamMaxBitRate: %w", err)
+		}
+	}
+	return &response, nil
+}
+
+// The out-arguments of the GetPPPEncryptionProtocol action.
+type WANPPPConnection1GetPPPEncryptionProtocolResponse struct {
+	NewPPPEncryptionProtocol string // PPPEncryptionProtocol
+}
+
+// This method invokes the GetPPPEncryptionProtocol action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *WANPPPConnection1) GetPPPEncryptionProtocol(ctx context.Context) (*WANPPPConnection1GetPPPEncryptionProtocolResponse, error) {
+	out, err := self.igd.Invoke(ctx, self.Service, "GetPPPEncryptionProtocol", nil)
+	if err != nil {
+		return nil, fmt.Errorf("GetPPPEncryptionProtocol: %w", err)
+	}
+	var response WANPPPConnection1GetPPPEncryptionProtocolResponse
+	response.NewPPPEncryptionProtocol = out["NewPPPEncryptionProtocol"]
+	return &response, nil
+}
+
+// The out-arguments of the GetPPPCompressionProtocol action.
+type WANPPPConnection1GetPPPCompressionProtocolResponse struct {
+	NewPPPCompressionProtocol string // PPPCompressionProtocol
+}
+
+// This method invokes the GetPPPCompressionProtocol action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *WANPPPConnection1) GetPPPCompressionProtocol(ctx context.Context) (*WANPPPConnection1GetPPPCompressionProtocolResponse, error) {
+	out, err := self.igd.Invoke(ctx, self.Service, "GetPPPCompressionProtocol", nil)
+	if err != nil {
+		return nil, fmt.Errorf("GetPPPCompressionProtocol: %w", err)
+	}
+	var response WANPPPConnection1GetPPPCompressionProtocolResponse
+	response.NewPPPCompressionProtocol = out["NewPPPCompressionProtocol"]
+	return &response, nil
+}
+
+// The out-arguments of the GetPPPAuthenticationProtocol action.
+type WANPPPConnection1GetPPPAuthenticationProtocolResponse struct {
+	NewPPPAuthenticationProtocol string // PPPAuthenticationProtocol
+}
+
+// This method invokes the GetPPPAuthenticationProtocol action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *WANPPPConnection1) GetPPPAuthenticationProtocol(ctx context.Context) (*WANPPPConnection1GetPPPAuthenticationProtocolResponse, error) {
+	out, err := self.igd.Invoke(ctx, self.Service, "GetPPPAuthenticationProtocol", nil)
+	if err != nil {
+		return nil, fmt.Errorf("GetPPPAuthenticationProtocol: %w", err)
+	}
+	var response WANPPPConnection1GetPPPAuthenticationProtocolResponse
+	response.NewPPPAuthenticationProtocol = out["NewPPPAuthenticationProtocol"]
+	return &response, nil
+}
+
+// The out-arguments of the GetUserName action.
+type WANPPPConnection1GetUserNameResponse struct {
+	NewUserName string // UserName
+}
+
+// This method invokes the GetUserName action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *WANPPPConnection1) GetUserName(ctx context.Context) (*WANPPPConnection1GetUserNameResponse, error) {
+	out, err := self.igd.Invoke(ctx, self.Service, "GetUserName", nil)
+	if err != nil {
+		return nil, fmt.Errorf("GetUserName: %w", err)
+	}
+	var response WANPPPConnection1GetUserNameResponse
+	response.NewUserName = out["NewUserName"]
+	return &response, nil
+}
+
+// The out-arguments of the GetPassword action.
+type WANPPPConnection1GetPasswordResponse struct {
+	NewPassword string // Password
+}
+
+// This method invokes the GetPassword action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *WANPPPConnection1) GetPassword(ctx context.Context) (*WANPPPConnection1GetPasswordResponse, error) {
+	out, err := self.igd.Invoke(ctx, self.Service, "GetPassword", nil)
+	if err != nil {
+		return nil, fmt.Errorf("GetPassword: %w", err)
+	}
+	var response WANPPPConnection1GetPasswordResponse
+	response.NewPassword = out["NewPassword"]
+	return &response, nil
+}
+
+// The out-arguments of the GetAutoDisconnectTime action.
+type WANPPPConnection1GetAutoDisconnectTimeResponse struct {
+	NewAutoDisconnectTime uint32 // AutoDisconnectTime
+}
+
+// This method invokes the GetAutoDisconnectTime action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *WANPPPConnection1) GetAutoDisconnectTime(ctx context.Context) (*WANPPPConnection1GetAutoDisconnectTimeResponse, error) {
+	out, err := self.igd.Invoke(ctx, self.Service, "GetAutoDisconnectTime", nil)
+	if err != nil {
+		return nil, fmt.Errorf("GetAutoDisconnectTime: %w", err)
+	}
+	var response WANPPPConnection1GetAutoDisconnectTimeResponse
+	if value, ok := out["NewAutoDisconnectTime"]; ok {
+		if response.NewAutoDisconnectTime, err = parseUint[uint32](value, 32); err != nil {
+			return nil, fmt.Errorf("GetAutoDisconnectTime: NewAutoDisconnectTime: %w", err)
+		}
+	}
+	return &response, nil
+}
+
+// The out-arguments of the GetIdleDisconnectTime action.
+type WANPPPConnection1GetIdleDisconnectTimeResponse struct {
+	NewIdleDisconnectTime uint32 // IdleDisconnectTime
+}
+
+// This method invokes the GetIdleDisconnectTime action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *WANPPPConnection1) GetIdleDisconnectTime(ctx context.Context) (*WANPPPConnection1GetIdleDisconnectTimeResponse, error) {
+	out, err := self.igd.Invoke(ctx, self.Service, "GetIdleDisconnectTime", nil)
+	if err != nil {
+		return nil, fmt.Errorf("GetIdleDisconnectTime: %w", err)
+	}
+	var response WANPPPConnection1GetIdleDisconnectTimeResponse
+	if value, ok := out["NewIdleDisconnectTime"]; ok {
+		if response.NewIdleDisconnectTime, err = parseUint[uint32](value, 32); err != nil {
+			return nil, fmt.Errorf("GetIdleDisconnectTime: NewIdleDisconnectTime: %w", err)
+		}
+	}
+	return &response, nil
+}
+
+// The out-arguments of the GetWarnDisconnectDelay action.
+type WANPPPConnection1GetWarnDisconnectDelayResponse struct {
+	NewWarnDisconnectDelay uint32 // WarnDisconnectDelay
+}
+
+// This method invokes the GetWarnDisconnectDelay action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *WANPPPConnection1) GetWarnDisconnectDelay(ctx context.Context) (*WANPPPConnection1GetWarnDisconnectDelayResponse, error) {
+	out, err := self.igd.Invoke(ctx, self.Service, "GetWarnDisconnectDelay", nil)
+	if err != nil {
+		return nil, fmt.Errorf("GetWarnDisconnectDelay: %w", err)
+	}
+	var response WANPPPConnection1GetWarnDisconnectDelayResponse
+	if value, ok := out["NewWarnDisconnectDelay"]; ok {
+		if response.NewWarnDisconnectDelay, err = parseUint[uint32](value, 32); err != nil {
+			return nil, fmt.Errorf("GetWarnDisconnectDelay: NewWarnDisconnectDelay: %w", err)
+		}
+	}
+	return &response, nil
+}
+
+// The out-arguments of the GetNATRSIPStatus action.
+type WANPPPConnection1GetNATRSIPStatusResponse struct {
+	NewRSIPAvailable bool // RSIPAvailable
+	NewNATEnabled    bool // NATEnabled
+}
+
+// This method invokes the GetNATRSIPStatus action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *WANPPPConnection1) GetNATRSIPStatus(ctx context.Context) (*WANPPPConnection1GetNATRSIPStatusResponse, error) {
+	out, err := self.igd.Invoke(ctx, self.Service, "GetNATRSIPStatus", nil)
+	if err != nil {
+		return nil, fmt.Errorf("GetNATRSIPStatus: %w", err)
+	}
+	var response WANPPPConnection1GetNATRSIPStatusResponse
+	if value, ok := out["NewRSIPAvailable"]; ok {
+		if response.NewRSIPAvailable, err = parseBool(value); err != nil {
+			return nil, fmt.Errorf("GetNATRSIPStatus: NewRSIPAvailable: %w", err)
+		}
+	}
+	if value, ok := out["NewNATEnabled"]; ok {
+		if response.NewNATEnabled, err = parseBool(value); err != nil {
+			return nil, fmt.Errorf("GetNATRSIPStatus: NewNATEnabled: %w", err)
+		}
+	}
+	return &response, nil
+}
+
+// The in-arguments of the GetGenericPortMappingEntry action.
+type WANPPPConnection1GetGenericPortMappingEntryRequest struct {
+	NewPortMappingIndex uint16 // PortMappingNumberOfEntries
+}
+
+// The out-arguments of the GetGenericPortMappingEntry action.
+type WANPPPConnection1GetGenericPortMappingEntryResponse struct {
+	NewRemoteHost             string // RemoteHost
+	NewExternalPort           uint16 // ExternalPort
+	NewProtocol               string // PortMappingProtocol
+	NewInternalPort           uint16 // InternalPort
+	NewInternalClient         string // InternalClient
+	NewEnabled                bool   // PortMappingEnabled
+	NewPortMappingDescription string // PortMappingDescription
+	NewLeaseDuration          uint32 // PortMappingLeaseDuration
+}
+
+// This method invokes the GetGenericPortMappingEntry action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *WANPPPConnection1) GetGenericPortMappingEntry(ctx context.Context, request *WANPPPConnection1GetGenericPortMappingEntryRequest) (*WANPPPConnection1GetGenericPortMappingEntryResponse, error) {
+	out, err := self.igd.Invoke(ctx, self.Service, "GetGenericPortMappingEntry", map[string]string{
+		"NewPortMappingIndex": formatUint(uint64(request.NewPortMappingIndex)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("GetGenericPortMappingEntry: %w", err)
+	}
+	var response WANPPPConnection1GetGenericPortMappingEntryResponse
+	response.NewRemoteHost = out["NewRemoteHost"]
+	if value, ok := out["NewExternalPort"]; ok {
+		if response.NewExternalPort, err = parseUint[uint16](value, 16); err != nil {
+			return nil, fmt.Errorf("GetGenericPortMappingEntry: NewExternalPort: %w", err)
+		}
+	}
+	response.NewProtocol = out["NewProtocol"]
+	if value, ok := out["NewInternalPort"]; ok {
+		if response.NewInternalPort, err = parseUint[uint16](value, 16); err != nil {
+			return nil, fmt.Errorf("GetGenericPortMappingEntry: NewInternalPort: %w", err)
+		}
+	}
+	response.NewInternalClient = out["NewInternalClient"]
+	if value, ok := out["NewEnabled"]; ok {
+		if response.NewEnabled, err = parseBool(value); err != nil {
+			return nil, fmt.Errorf("GetGenericPortMappingEntry: NewEnabled: %w", err)
+		}
+	}
+	response.NewPortMappingDescription = out["NewPortMappingDescription"]
+	if value, ok := out["NewLeaseDuration"]; ok {
+		if response.NewLeaseDuration, err = parseUint[uint32](value, 32); err != nil {
+			return nil, fmt.Errorf("GetGenericPortMappingEntry: NewLeaseDuration: %w", err)
+		}
+	}
+	return &response, nil
+}
+
+// The in-arguments of the GetSpecificPortMappingEntry action.
+type WANPPPConnection1GetSpecificPortMappingEntryRequest struct {
+	NewRemoteHost   string // RemoteHost
+	NewExternalPort uint16 // ExternalPort
+	NewProtocol     string // PortMappingProtocol
+}
+
+// The out-arguments of the GetSpecificPortMappingEntry action.
+type WANPPPConnection1GetSpecificPortMappingEntryResponse struct {
+	NewInternalPort           uint16 // InternalPort
+	NewInternalClient         string // InternalClient
+	NewEnabled                bool   // PortMappingEnabled
+	NewPortMappingDescription string // PortMappingDescription
+	NewLeaseDuration          uint32 // PortMappingLeaseDuration
+}
+
+// This method invokes the GetSpecificPortMappingEntry action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *WANPPPConnection1) GetSpecificPortMappingEntry(ctx context.Context, request *WANPPPConnection1GetSpecificPortMappingEntryRequest) (*WANPPPConnection1GetSpecificPortMappingEntryResponse, error) {
+	out, err := self.igd.Invoke(ctx, self.Service, "GetSpecificPortMappingEntry", map[string]string{
+		"NewRemoteHost":   request.NewRemoteHost,
+		"NewExternalPort": formatUint(uint64(request.NewExternalPort)),
+		"NewProtocol":     request.NewProtocol,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("GetSpecificPortMappingEntry: %w", err)
+	}
+	var response WANPPPConnection1GetSpecificPortMappingEntryResponse
+	if value, ok := out["NewInternalPort"]; ok {
+		if response.NewInternalPort, err = parseUint[uint16](value, 16); err != nil {
+			return nil, fmt.Errorf("GetSpecificPortMappingEntry: NewInternalPort: %w", err)
+		}
+	}
+	response.NewInternalClient = out["NewInternalClient"]
+	if value, ok := out["NewEnabled"]; ok {
+		if response.NewEnabled, err = parseBool(value); err != nil {
+			return nil, fmt.Errorf("GetSpecificPortMappingEntry: NewEnabled: %w", err)
+		}
+	}
+	response.NewPortMappingDescription = out["NewPortMappingDescription"]
+	if value, ok := out["NewLeaseDuration"]; ok {
+		if response.NewLeaseDuration, err = parseUint[uint32](value, 32); err != nil {
+			return nil, fmt.Errorf("GetSpecificPortMappingEntry: NewLeaseDuration: %w", err)
+		}
+	}
+	return &response, nil
+}
+
+// The in-arguments of the AddPortMapping action.
+type WANPPPConnection1AddPortMappingRequest struct {
+	NewRemoteHost             string // RemoteHost
+	NewExternalPort           uint16 // ExternalPort
+	NewProtocol               string // PortMappingProtocol
+	NewInternalPort           uint16 // InternalPort
+	NewInternalClient         string // InternalClient
+	NewEnabled                bool   // PortMappingEnabled
+	NewPortMappingDescription string // PortMappingDescription
+	NewLeaseDuration          uint32 // PortMappingLeaseDuration
+}
+
+// This method invokes the AddPortMapping action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *WANPPPConnection1) AddPortMapping(ctx context.Context, request *WANPPPConnection1AddPortMappingRequest) error {
+	_, err := self.igd.Invoke(ctx, self.Service, "AddPortMapping", map[string]string{
+		"NewRemoteHost":             request.NewRemoteHost,
+		"NewExternalPort":           formatUint(uint64(request.NewExternalPort)),
+		"NewProtocol":               request.NewProtocol,
+		"NewInternalPort":           formatUint(uint64(request.NewInternalPort)),
+		"NewInternalClient":         request.NewInternalClient,
+		"NewEnabled":                formatBool(request.NewEnabled),
+		"NewPortMappingDescription": request.NewPortMappingDescription,
+		"NewLeaseDuration":          formatUint(uint64(request.NewLeaseDuration)),
+	})
+	if err != nil {
+		return fmt.Errorf("AddPortMapping: %w", err)
+	}
+	return nil
+}
+
+// The in-arguments of the DeletePortMapping action.
+type WANPPPConnection1DeletePortMappingRequest struct {
+	NewRemoteHost   string // RemoteHost
+	NewExternalPort uint16 // ExternalPort
+	NewProtocol     string // PortMappingProtocol
+}
+
+// This method invokes the DeletePortMapping action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *WANPPPConnection1) DeletePortMapping(ctx context.Context, request *WANPPPConnection1DeletePortMappingRequest) error {
+	_, err := self.igd.Invoke(ctx, self.Service, "DeletePortMapping", map[string]string{
+		"NewRemoteHost":   request.NewRemoteHost,
+		"NewExternalPort": formatUint(uint64(request.NewExternalPort)),
+		"NewProtocol":     request.NewProtocol,
+	})
+	if err != nil {
+		return fmt.Errorf("DeletePortMapping: %w", err)
+	}
+	return nil
+}
+
+// The out-arguments of the GetExternalIPAddress action.
+type WANPPPConnection1GetExternalIPAddressResponse struct {
+	NewExternalIPAddress string // ExternalIPAddress
+}
+
+// This method invokes the GetExternalIPAddress action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *WANPPPConnection1) GetExternalIPAddress(ctx context.Context) (*WANPPPConnection1GetExternalIPAddressResponse, error) {
+	out, err := self.igd.Invoke(ctx, self.Service, "GetExternalIPAddress", nil)
+	if err != nil {
+		return nil, fmt.Errorf("GetExternalIPAddress: %w", err)
+	}
+	var response WANPPPConnection1GetExternalIPAddressResponse
+	response.NewExternalIPAddress = out["NewExternalIPAddress"]
+	return &response, nil
+}
@@ -0,0 +1,499 @@
+package goupnp
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"log/slog"
+)
+
+const (
+	// The duration, in seconds, subscriptions are requested for. IGDs are free
+	// to grant a different one.
+	genaTimeout = 1800
+	// The bound on the time spent on a single SUBSCRIBE or UNSUBSCRIBE
+	genaRequestTimeout = 10 * time.Second
+	eventNamespace     = "urn:schemas-upnp-org:event-1-0"
+)
+
+// This type describes a change of the evented state variables of a service,
+// as notified by the IGD, e.g. of ExternalIPAddress, ConnectionStatus or
+// PortMappingNumberOfEntries for a connection service.
+type Event struct {
+	Service *Service
+	// The sequence number of the event, 0 for the initial event sent upon
+	// subscribing which holds the value of every evented state variable
+	Seq uint32
+	// Whether events were lost before this one, in which case variables
+	// absent from Variables may have changed unnoticed
+	Missed    bool
+	Variables map[string]string
+}
+
+// This type is returned by Subscribe() and delivers the events of a service
+// until closed. The subscription is renewed with the IGD as long as it is
+// open.
+type Subscription struct {
+	Service *Service
+	// Sent every Event of the service, it should be drained as later events
+	// are otherwise held up. It is closed upon Close() or once the
+	// subscription could not be renewed.
+	Events chan *Event
+
+	igd         *IGD
+	eventSubURL *url.URL
+	path        string
+	stop        chan struct{}
+	endOnce     sync.Once
+	closeOnce   sync.Once
+
+	// Guards the fields below
+	mutex   sync.Mutex
+	sid     string
+	seq     uint32
+	timeout time.Duration
+	ended   bool
+	// The events received while subscribing, before the SID is known
+	early []heldEvent
+	// The deliveries in progress, Events being closed once they are over
+	sending sync.WaitGroup
+}
+
+// This type is an event held along with the SID it was received with.
+type heldEvent struct {
+	sid   string
+	event *Event
+}
+
+// This type is the HTTP server an IGD sends the events of its subscriptions
+// to, which it shares between them. It is guarded by the mutex of the IGD.
+type eventServer struct {
+	server        *http.Server
+	callback      string
+	subscriptions map[string]*Subscription
+	next          int
+}
+
+// This method subscribes to the events of the passed service of the IGD, which
+// must be one of those found in its Description(). The events are received
+// by an HTTP server listening on the local address the IGD was discovered
+// from.
+//
+// Once subscribed, which is when this method returns, the IGD sends an
+// initial event holding the value of every evented state variable.
+func (self *IGD) Subscribe(ctx context.Context, service *Service) (*Subscription, error) {
+	eventSubURL, err := self.resolveURL(service.EventSubURL)
+	if err != nil {
+		return nil, fmt.Errorf("No event subscription URL: %w", err)
+	}
+	subscription := &Subscription{
+		Service:     service,
+		Events:      make(chan *Event, 8),
+		igd:         self,
+		eventSubURL: eventSubURL,
+		stop:        make(chan struct{}),
+	}
+	callback, err := self.register(subscription)
+	if err != nil {
+		return nil, err
+	}
+	if err := subscription.subscribe(ctx, callback); err != nil {
+		self.unregister(subscription)
+		return nil, err
+	}
+	go subscription.renew(callback)
+	return subscription, nil
+}
+
+// This method subscribes afresh, as opposed to renewing the subscription.
+func (self *Subscription) subscribe(ctx context.Context, callback string) error {
+	// The initial event may well arrive before the response, it is then
+	// held until the response tells whether its SID is ours
+	self.mutex.Lock()
+	self.sid, self.seq, self.early = "", 0, nil
+	self.mutex.Unlock()
+
+	header, err := genaRequest(ctx, "SUBSCRIBE", self.eventSubURL, map[string]string{
+		"CALLBACK": "<" + callback + ">",
+		"NT":       "upnp:event",
+		"TIMEOUT":  "Second-" + strconv.Itoa(genaTimeout),
+	})
+	if err != nil {
+		return err
+	}
+	sid := header.Get("SID")
+	if sid == "" {
+		return errors.New("SUBSCRIBE: no SID in response")
+	}
+
+	self.mutex.Lock()
+	self.sid = sid
+	self.timeout = parseGENATimeout(header.Get("TIMEOUT"))
+	var events []*Event
+	for _, held := range self.early {
+		if held.sid == sid {
+			self.sequence(held.event)
+			events = append(events, held.event)
+		} else {
+			slog.Debug("Event of another subscription dropped", "sid", held.sid)
+		}
+	}
+	self.early = nil
+	if len(events) > 0 && !self.ended {
+		self.sending.Add(1)
+		go self.send(events...)
+	}
+	self.mutex.Unlock()
+	slog.Debug("Subscribed", "service", self.Service.ServiceType, "sid", sid,
+		"timeout", self.timeout)
+	return nil
+}
+
+// This method renews the subscription at half its timeout until it is
+// closed, subscribing afresh should the IGD have forgotten about it, e.g.
+// after rebooting. The subscription ends if neither succeeds.
+func (self *Subscription) renew(callback string) {
+	for {
+		self.mutex.Lock()
+		sid, timeout := self.sid, self.timeout
+		self.mutex.Unlock()
+		if timeout <= 0 {
+			// The IGD granted an infinite subscription
+			<-self.stop
+			return
+		}
+
+		timer := time.NewTimer(timeout / 2)
+		select {
+		case <-self.stop:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), genaRequestTimeout)
+		header, err := genaRequest(ctx, "SUBSCRIBE", self.eventSubURL, map[string]string{
+			"SID":     sid,
+			"TIMEOUT": "Second-" + strconv.Itoa(genaTimeout),
+		})
+		if err == nil {
+			self.mutex.Lock()
+			self.timeout = parseGENATimeout(header.Get("TIMEOUT"))
+			self.mutex.Unlock()
+		} else {
+			slog.Info("Failed to renew subscription, subscribing again",
+				"service", self.Service.ServiceType, "error", err)
+			err = self.subscribe(ctx, callback)
+		}
+		cancel()
+		if err != nil {
+			slog.Warn("Subscription lost", "service", self.Service.ServiceType,
+				"error", err)
+			self.end()
+			return
+		}
+	}
+}
+
+// This method cancels the subscription with the IGD and closes Events. It
+// blocks until the IGD has answered and returns the error it reported, if
+// any. Calling it more than once has no effect.
+func (self *Subscription) Close() (err error) {
+	self.closeOnce.Do(func() {
+		ended := self.end()
+		self.mutex.Lock()
+		sid := self.sid
+		self.mutex.Unlock()
+		if ended && sid != "" {
+			ctx, cancel := context.WithTimeout(context.Background(), genaRequestTimeout)
+			defer cancel()
+			_, err = genaRequest(ctx, "UNSUBSCRIBE", self.eventSubURL,
+				map[string]string{"SID": sid})
+		}
+	})
+	return
+}
+
+// This method stops the subscription from receiving events and closes Events,
+// returning false if it had already ended.
+func (self *Subscription) end() (ended bool) {
+	self.endOnce.Do(func() {
+		// Unblocks any delivery in progress
+		close(self.stop)
+		self.igd.unregister(self)
+
+		self.mutex.Lock()
+		self.ended = true
+		self.mutex.Unlock()
+		self.sending.Wait()
+		close(self.Events)
+		ended = true
+	})
+	return
+}
+
+// This method accepts the passed event, received with the passed SID,
+// provided the subscription has not ended, returning true if it is then to be
+// passed to send(). Events received before the SUBSCRIBE response are held
+// until it tells their SID, those of any other SID being dropped.
+func (self *Subscription) accept(sid string, event *Event) (bool, error) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	if self.ended {
+		return false, errors.New("Subscription ended")
+	}
+	if self.sid == "" {
+		self.early = append(self.early, heldEvent{sid, event})
+		return false, nil
+	}
+	if sid != self.sid {
+		return false, fmt.Errorf("Unknown SID %s", sid)
+	}
+	self.sequence(event)
+	self.sending.Add(1)
+	return true, nil
+}
+
+// This method records whether events were lost before the passed one, and
+// the sequence number expected next. The mutex must be held.
+func (self *Subscription) sequence(event *Event) {
+	event.Missed = event.Seq != self.seq
+	// Sequence numbers wrap to 1 rather than 0, which only ever denotes the
+	// initial event
+	if event.Seq == math.MaxUint32 {
+		self.seq = 1
+	} else {
+		self.seq = event.Seq + 1
+	}
+}
+
+// This method sends the passed accepted events on Events, unless the
+// subscription ends meanwhile. It is called without holding the mutex, so
+// that a slow reader of Events does not hold up renewals.
+func (self *Subscription) send(events ...*Event) {
+	defer self.sending.Done()
+	for _, event := range events {
+		select {
+		case self.Events <- event:
+		case <-self.stop:
+			return
+		}
+	}
+}
+
+// This method registers the passed subscription with the event server of the
+// IGD, starting it if need be, and returns the callback URL the IGD should
+// send events to.
+func (self *IGD) register(subscription *Subscription) (string, error) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	if self.events == nil {
+		server, err := self.startEventServer()
+		if err != nil {
+			return "", err
+		}
+		self.events = server
+	}
+	self.events.next++
+	subscription.path = "/events/" + strconv.Itoa(self.events.next)
+	self.events.subscriptions[subscription.path] = subscription
+	return self.events.callback + subscription.path, nil
+}
+
+// This method removes the passed subscription from the event server of the
+// IGD, stopping it once no subscription is left.
+func (self *IGD) unregister(subscription *Subscription) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	if self.events == nil {
+		return
+	}
+	delete(self.events.subscriptions, subscription.path)
+	if len(self.events.subscriptions) == 0 {
+		self.events.server.Close()
+		self.events = nil
+	}
+}
+
+// This method starts an HTTP server receiving events on the local address
+// the IGD was discovered from.
+func (self *IGD) startEventServer() (*eventServer, error) {
+	host := self.iface.String()
+	if self.iface.IsLinkLocalUnicast() && self.link != nil {
+		host += "%" + self.link.Name
+	}
+	listener, err := net.Listen("tcp", net.JoinHostPort(host, "0"))
+	if err != nil {
+		return nil, err
+	}
+	_, port, _ := net.SplitHostPort(listener.Addr().String())
+
+	server := &eventServer{
+		server:        &http.Server{Handler: http.HandlerFunc(self.serveEvent)},
+		callback:      "http://" + net.JoinHostPort(self.iface.String(), port),
+		subscriptions: make(map[string]*Subscription),
+	}
+	go server.server.Serve(listener)
+	return server, nil
+}
+
+// This method handles the NOTIFY requests the IGD sends to the event server.
+func (self *IGD) serveEvent(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "NOTIFY" {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	self.mutex.Lock()
+	var subscription *Subscription
+	if self.events != nil {
+		subscription = self.events.subscriptions[r.URL.Path]
+	}
+	self.mutex.Unlock()
+	if subscription == nil {
+		w.WriteHeader(http.StatusPreconditionFailed)
+		return
+	}
+
+	if r.Header.Get("NT") != "upnp:event" || r.Header.Get("NTS") != "upnp:propchange" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	seq, err := strconv.ParseUint(strings.TrimSpace(r.Header.Get("SEQ")), 10, 32)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return
+	}
+	slog.Debug("Event", "service", subscription.Service.ServiceType, "seq", seq,
+		"content", string(body))
+	variables, err := parsePropertySet(body, self.strict)
+	if err != nil {
+		slog.Warn("Bad event", "error", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	event := &Event{
+		Service:   subscription.Service,
+		Seq:       uint32(seq),
+		Variables: variables,
+	}
+	accepted, err := subscription.accept(r.Header.Get("SID"), event)
+	if err != nil {
+		slog.Debug("Event rejected", "error", err)
+		w.WriteHeader(http.StatusPreconditionFailed)
+		return
+	}
+	if accepted {
+		// The IGD is answered first, lest a slow reader of Events make it
+		// take us for a dead subscriber
+		w.WriteHeader(http.StatusOK)
+		if flusher, ok := w.(http.Flusher); ok {
+			flusher.Flush()
+		}
+		subscription.send(event)
+	}
+}
+
+// This function performs the passed GENA request on eventSubURL and returns
+// the headers of the response.
+func genaRequest(ctx context.Context, method string, eventSubURL *url.URL,
+	headers map[string]string) (http.Header, error) {
+	req, err := http.NewRequestWithContext(ctx, method, eventSubURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	// Headers are set as is rather than canonicalized, as some IGDs compare
+	// them case-sensitively
+	for name, value := range headers {
+		req.Header[name] = []string{value}
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	// We got something back, lets not leak it
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: %s", method, resp.Status)
+	}
+	return resp.Header, nil
+}
+
+// This function parses the value of a TIMEOUT header, such as "Second-1800",
+// returning 0 for an infinite timeout. Unparsable values are taken to be the
+// requested timeout.
+func parseGENATimeout(str string) time.Duration {
+	str = strings.TrimSpace(str)
+	if len(str) < 7 || !strings.EqualFold(str[:7], "Second-") {
+		return genaTimeout * time.Second
+	}
+	if strings.EqualFold(str[7:], "infinite") {
+		return 0
+	}
+	seconds, err := strconv.Atoi(str[7:])
+	if err != nil || seconds <= 0 {
+		return genaTimeout * time.Second
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// This function returns the value of every state variable found in the passed
+// event body, a propertyset holding one property per variable.
+func parsePropertySet(body []byte, strict bool) (map[string]string, error) {
+	decoder := newXMLDecoder(body, strict)
+	ret := make(map[string]string)
+	var (
+		depth int
+		name  string
+		value strings.Builder
+	)
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, fmt.Errorf("Malformed propertyset: %w", err)
+		}
+		switch token := token.(type) {
+		case xml.StartElement:
+			depth++
+			switch depth {
+			case 1:
+				if err := checkNamespace(token.Name, eventNamespace, strict); err != nil {
+					return nil, fmt.Errorf("Malformed propertyset: %w", err)
+				}
+			case 3:
+				// propertyset > property > variable
+				name = token.Name.Local
+				value.Reset()
+			}
+		case xml.CharData:
+			if depth == 3 {
+				value.Write(token)
+			}
+		case xml.EndElement:
+			if depth == 3 {
+				ret[name] = value.String()
+			}
+			depth--
+			if depth == 0 {
+				return ret, nil
+			}
+		}
+	}
+}
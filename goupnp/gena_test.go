@@ -0,0 +1,239 @@
+package goupnp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+const examplePropertySet = `<?xml version="1.0"?>
+<e:propertyset xmlns:e="urn:schemas-upnp-org:event-1-0">
+<e:property><ConnectionStatus>Connected</ConnectionStatus></e:property>
+<e:property><ExternalIPAddress>203.0.113.7</ExternalIPAddress></e:property>
+<e:property><PortMappingNumberOfEntries>2</PortMappingNumberOfEntries></e:property>
+</e:propertyset>
+`
+
+func TestPropertySetParsing(t *testing.T) {
+	variables, err := parsePropertySet([]byte(examplePropertySet), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(variables) != 3 || variables["ConnectionStatus"] != "Connected" ||
+		variables["ExternalIPAddress"] != "203.0.113.7" ||
+		variables["PortMappingNumberOfEntries"] != "2" {
+		t.Errorf("Variables incorrectly parsed as %v", variables)
+	}
+
+	if _, err := parsePropertySet([]byte("<e:propertyset"), false); err == nil {
+		t.Error("Expected an error for a truncated propertyset")
+	}
+}
+
+func TestGENATimeoutParsing(t *testing.T) {
+	timeouts := map[string]time.Duration{
+		"Second-300":      300 * time.Second,
+		"second-60":       60 * time.Second,
+		"Second-infinite": 0,
+		"":                genaTimeout * time.Second,
+		"Second-":         genaTimeout * time.Second,
+	}
+	for str, expected := range timeouts {
+		if actual := parseGENATimeout(str); actual != expected {
+			t.Errorf("%q parsed as %v, expected %v", str, actual, expected)
+		}
+	}
+}
+
+// This function sends an event to the passed callback as an IGD would.
+func notify(t *testing.T, callback, sid string, seq uint32, body string) int {
+	req, _ := http.NewRequest("NOTIFY", callback, strings.NewReader(body))
+	req.Header["NT"] = []string{"upnp:event"}
+	req.Header["NTS"] = []string{"upnp:propchange"}
+	req.Header["SID"] = []string{sid}
+	req.Header["SEQ"] = []string{fmt.Sprint(seq)}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+func TestSubscription(t *testing.T) {
+	const sid = "uuid:00000000-0000-0000-0000-000000000042"
+
+	callbacks := make(chan string, 1)
+	unsubscribed := make(chan string, 1)
+	igd := newTestIGDWith(t, loopbackInterface, belkinDescription, nil,
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case "SUBSCRIBE":
+				if r.Header.Get("NT") != "upnp:event" {
+					w.WriteHeader(http.StatusPreconditionFailed)
+					return
+				}
+				callbacks <- strings.Trim(r.Header.Get("CALLBACK"), "<>")
+				w.Header().Set("SID", sid)
+				w.Header().Set("TIMEOUT", "Second-1800")
+			case "UNSUBSCRIBE":
+				unsubscribed <- r.Header.Get("SID")
+			}
+		}))
+
+	_, service := igd.Description().Device.FindService(connectionTypeStringWANIP)
+	subscription, err := igd.Subscribe(context.Background(), service)
+	if err != nil {
+		t.Fatal(err)
+	}
+	callback := <-callbacks
+
+	if status := notify(t, callback, sid, 0, examplePropertySet); status != http.StatusOK {
+		t.Fatalf("Initial event answered with %v", status)
+	}
+	event := <-subscription.Events
+	if event.Service != service || event.Seq != 0 || event.Missed ||
+		event.Variables["ExternalIPAddress"] != "203.0.113.7" {
+		t.Errorf("Initial event incorrectly received as %+v", event)
+	}
+
+	// Event 1 is lost
+	notify(t, callback, sid, 2, examplePropertySet)
+	if event := <-subscription.Events; event.Seq != 2 || !event.Missed {
+		t.Errorf("Missed event not reported in %+v", event)
+	}
+	if status := notify(t, callback, "uuid:other", 3, examplePropertySet); status != http.StatusPreconditionFailed {
+		t.Errorf("Event of another subscription answered with %v", status)
+	}
+
+	if err := subscription.Close(); err != nil {
+		t.Error(err)
+	}
+	if actual := <-unsubscribed; actual != sid {
+		t.Errorf("Unsubscribed %q rather than %q", actual, sid)
+	}
+	if _, ok := <-subscription.Events; ok {
+		t.Error("Events not closed")
+	}
+	if igd.events != nil {
+		t.Error("Event server still running without subscriptions")
+	}
+}
+
+func TestSubscriptionRenewal(t *testing.T) {
+	requests := make(chan http.Header, 4)
+	igd := newTestIGDWith(t, loopbackInterface, belkinDescription, nil,
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != "SUBSCRIBE" {
+				return
+			}
+			requests <- r.Header
+			// The IGD forgot about the subscription, e.g. it rebooted
+			if r.Header.Get("SID") != "" {
+				w.WriteHeader(http.StatusPreconditionFailed)
+				return
+			}
+			w.Header().Set("SID", fmt.Sprint("uuid:", len(requests)))
+			w.Header().Set("TIMEOUT", "Second-1")
+		}))
+
+	_, service := igd.Description().Device.FindService(connectionTypeStringWANIP)
+	subscription, err := igd.Subscribe(context.Background(), service)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer subscription.Close()
+
+	<-requests
+	if renewal := <-requests; renewal.Get("SID") == "" || renewal.Get("CALLBACK") != "" {
+		t.Errorf("Renewal sent as %v", renewal)
+	}
+	if resubscription := <-requests; resubscription.Get("CALLBACK") == "" {
+		t.Errorf("Subscription not renewed afresh but as %v", resubscription)
+	}
+}
+
+func TestSubscriptionSlowReader(t *testing.T) {
+	const sid = "uuid:slow"
+	callbacks := make(chan string, 1)
+	renewals := make(chan string, 8)
+	igd := newTestIGDWith(t, loopbackInterface, belkinDescription, nil,
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != "SUBSCRIBE" {
+				return
+			}
+			if callback := r.Header.Get("CALLBACK"); callback != "" {
+				callbacks <- strings.Trim(callback, "<>")
+			} else {
+				renewals <- r.Header.Get("SID")
+			}
+			w.Header().Set("SID", sid)
+			w.Header().Set("TIMEOUT", "Second-1")
+		}))
+
+	subscription, err := igd.Subscribe(context.Background(), igd.ConnectionService())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer subscription.Close()
+	callback := <-callbacks
+
+	// Events are answered, and the subscription renewed, even though Events
+	// is not read and its buffer overflows
+	for seq := range uint32(cap(subscription.Events) + 2) {
+		if status := notify(t, callback, sid, seq, examplePropertySet); status != http.StatusOK {
+			t.Fatalf("Event %d answered with %v", seq, status)
+		}
+	}
+	select {
+	case renewal := <-renewals:
+		if renewal != sid {
+			t.Errorf("Renewed %q rather than %q", renewal, sid)
+		}
+	case <-time.After(2 * time.Second):
+		t.Error("Subscription not renewed while Events is not read")
+	}
+	if event := <-subscription.Events; event.Seq != 0 {
+		t.Errorf("First event received as %+v", event)
+	}
+}
+
+func TestSubscriptionEarlyEvents(t *testing.T) {
+	igd := newTestIGDWith(t, loopbackInterface, belkinDescription, nil,
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != "SUBSCRIBE" {
+				return
+			}
+			// Events arrive before the response, a late one of an expired
+			// subscription along with the initial one
+			callback := strings.Trim(r.Header.Get("CALLBACK"), "<>")
+			if status := notify(t, callback, "uuid:expired", 5, examplePropertySet); status != http.StatusOK {
+				t.Errorf("Early event answered with %v", status)
+			}
+			notify(t, callback, "uuid:new", 0, examplePropertySet)
+			w.Header().Set("SID", "uuid:new")
+			w.Header().Set("TIMEOUT", "Second-1800")
+		}))
+
+	subscription, err := igd.Subscribe(context.Background(), igd.ConnectionService())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer subscription.Close()
+	select {
+	case event := <-subscription.Events:
+		if event.Seq != 0 || event.Missed {
+			t.Errorf("Initial event received as %+v", event)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Initial event not received")
+	}
+	select {
+	case event := <-subscription.Events:
+		t.Errorf("Event of another subscription received as %+v", event)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -0,0 +1,13 @@
+package goupnp
+
+// The clients of the standard IGD services are generated from their SCPD
+// documents, found in the scpd directory.
+
+//go:generate go run ../upnpgen -scpd scpd/WANIPConnection1.xml -type urn:schemas-upnp-org:service:WANIPConnection:1 -name WANIPConnection1 -o gen_wanipconnection1.go
+//go:generate go run ../upnpgen -scpd scpd/WANPPPConnection1.xml -type urn:schemas-upnp-org:service:WANPPPConnection:1 -name WANPPPConnection1 -o gen_wanpppconnection1.go
+//go:generate go run ../upnpgen -scpd scpd/WANCommonInterfaceConfig1.xml -type urn:schemas-upnp-org:service:WANCommonInterfaceConfig:1 -name WANCommonInterfaceConfig1 -o gen_wancommoninterfaceconfig1.go
+//go:generate go run ../upnpgen -scpd scpd/Layer3Forwarding1.xml -type urn:schemas-upnp-org:service:Layer3Forwarding:1 -name Layer3Forwarding1 -o gen_layer3forwarding1.go
+//go:generate go run ../upnpgen -scpd scpd/LANHostConfigManagement1.xml -type urn:schemas-upnp-org:service:LANHostConfigManagement:1 -name LANHostConfigManagement1 -o gen_lanhostconfigmanagement1.go
+//go:generate go run ../upnpgen -scpd scpd/WANDSLLinkConfig1.xml -type urn:schemas-upnp-org:service:WANDSLLinkConfig:1 -name WANDSLLinkConfig1 -o gen_wandsllinkconfig1.go
+//go:generate go run ../upnpgen -scpd scpd/WANEthernetLinkConfig1.xml -type urn:schemas-upnp-org:service:WANEthernetLinkConfig:1 -name WANEthernetLinkConfig1 -o gen_wanethernetlinkconfig1.go
+//go:generate go run ../upnpgen -scpd scpd/WANCableLinkConfig1.xml -type urn:schemas-upnp-org:service:WANCableLinkConfig:1 -name WANCableLinkConfig1 -o gen_wancablelinkconfig1.go
@@ -0,0 +1,27 @@
+package goupnp
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestGeneratedClient(t *testing.T) {
+	igd := newTestIGDWith(t, loopbackInterface, belkinDescription, nil,
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(exampleBelkinSOAP))
+		}))
+
+	clients := igd.WANIPConnection1Clients()
+	if len(clients) != 1 || len(igd.WANPPPConnection1Clients()) != 0 {
+		t.Fatalf("Expected exactly one WANIPConnection client, got %v", clients)
+	}
+	status, err := clients[0].GetStatusInfo(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.NewConnectionStatus != "Connected" ||
+		status.NewLastConnectionError != "ERROR_NONE" || status.NewUptime != 194979 {
+		t.Errorf("Status incorrectly parsed as %+v", status)
+	}
+}
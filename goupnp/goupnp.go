@@ -2,14 +2,17 @@
 package goupnp
 
 import (
-	"errors"
+	"context"
 	"fmt"
 	"io"
 
 	"net"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"log/slog"
 )
@@ -44,87 +47,100 @@ func (self *PortMapping) String() string {
 // NOTA BENE Using instances of this struct not retured by the appropriate
 // function call has undefined behaviour
 type IGD struct {
+	// The connection service the IGD is bound to, see
+	// SelectConnectionService(), along with its control URL and type, all
+	// guarded by mutex
+	connection *Service
 	controlURL *url.URL
 	upnptype   string
-	iface      net.IP
+
+	iface net.IP
+	// The IPv4 address port mappings are created for, which differs from
+	// iface for IGDs found over IPv6 and is nil if the interface has none
+	internalClient net.IP
+	rank           Rank
+	// nil when the IGD does not provide WANIPv6FirewallControl
+	firewallURL *url.URL
+
+	description *DeviceDescription
+	descURL     *url.URL
+	link        *net.Interface
+
+	// Whether documents must conform to the specifications, see
+	// DiscoveryOptions.Strict
+	strict bool
+
+	// SCPDs fetched so far and the server receiving events, nil when there
+	// are no subscriptions, also guarded by mutex
+	mutex  sync.Mutex
+	scpds  map[*Service]*scpdEntry
+	events *eventServer
 }
 
 func (self *IGD) String() string {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
 	return self.controlURL.String()
 }
 
-// This function returns a channel which will be sent the first IGD it finds in
-// traversing `net.InterfaceAddrs()` with IP addresses in the private network
-// range.
+// This method returns the device description of the IGD, which notably tells
+// which router model it is and lists every service it provides. It must not
+// be modified.
+func (self *IGD) Description() *DeviceDescription {
+	return self.description
+}
+
+// This method returns the URL the device description of the IGD was fetched
+// from.
+func (self *IGD) DescriptionURL() *url.URL {
+	return self.descURL
+}
+
+// This method resolves a URL found in the description of the IGD, such as the
+// SCPDURL of a service, see DeviceDescription.ResolveURL(). Link-local hosts
+// are given the zone of the interface the IGD was found on.
+func (self *IGD) resolveURL(raw string) (*url.URL, error) {
+	u, err := self.description.ResolveURL(raw, self.descURL)
+	if err != nil {
+		return nil, err
+	}
+	setZone(u, self.link)
+	return u, nil
+}
+
+// This method returns the Rank the IGD was given during discovery, which
+// explains why it was or was not preferred over other IGDs found at the same
+// time.
+func (self *IGD) Rank() Rank {
+	return self.rank
+}
+
+// This function returns a channel which will be sent every IGD found in
+// traversing `net.Interfaces()` with IP addresses in the private network
+// range, best ranked first, and then closed. The first value is the IGD acting
+// as our default gateway whenever one could be found. See Rank for the details
+// of the ordering.
 //
-// The channel this function returns should be listened on to avoid leaking
-// goroutines. Additionally the listener must check whether the channel the
-// value returned by the channel against nil, to ensure that an IGD was indeed
-// found.
+// The channel this function returns must be read until it is closed to avoid
+// leaking goroutines, callers only interested in the best IGD may read the
+// first element of the slice DiscoverAllIGDs() sends instead. Additionally the
+// listener must check the value returned by the channel against nil, to ensure
+// that an IGD was indeed found.
 func DiscoverIGD() (ret chan *IGD) {
+	return DiscoverIGDWithOptions(nil)
+}
+
+// This function behaves as DiscoverIGD() but only searches on the local
+// interfaces allowed by the passed options. A nil opts is equivalent to the
+// zero DiscoveryOptions.
+func DiscoverIGDWithOptions(opts *DiscoveryOptions) (ret chan *IGD) {
 	// Create the channel we will return
 	ret = make(chan *IGD)
 
 	// Do the work asynchronously
 	go func() {
-		// For each and every local address in the private network range
-		bindLocalAddrs := localPrivateAddrs()
-		slog.Debug("Found private network interfaces", "count", len(bindLocalAddrs))
-		for i := range bindLocalAddrs {
-			// Use SSDP to search for a UPnP-enabled IGD
-			descURL, ok := discoverIGDDescriptionURL(bindLocalAddrs[i])
-
-			if ok {
-				// If we found one, we go fetch its description XML
-				resp, err := http.Get(descURL.String())
-				if err == nil {
-					// We got something back, lets not leak it
-					defer resp.Body.Close()
-					// We read in the whole description into memory We might
-					// envisage at a later date putting an upperbound on the
-					// buffer, however there is no risk of buffer overflow, so
-					// it is a low priority
-					body, err := io.ReadAll(resp.Body)
-					if err == nil {
-						slog.Debug("Description XML", "content", string(body))
-						// Parse the XML and extract relevant information
-						upnptype, controlURL, err := getConnectionControlURL(body)
-						if err == nil {
-							var igd IGD
-							// It worked, lets now try and wrap it in an igd struct
-							igd.controlURL, err = url.Parse(controlURL)
-							if err != nil {
-								slog.Warn("Failed to parse URL", "url", controlURL)
-							} else {
-								// Some routers erroniously do not provide a base URL
-								// so we check if this is not an absoulte URL, we attempt
-								// to guess valid values using the SSDP information
-								// we have gathered.
-								if !igd.controlURL.IsAbs() {
-									igd.controlURL.Scheme = "http"
-								}
-								if igd.controlURL.Host == "" {
-									igd.controlURL.Host = descURL.Host
-								}
-
-								// Lets track the type as well, in order to make
-								// the correct calls down the line
-								igd.upnptype = upnptype
-								// We now add the local binding address to
-								// enable the simple AddLocalPortRedirection
-								// method
-								igd.iface = bindLocalAddrs[i].IP
-
-								ret <- &igd
-							}
-						} else {
-							slog.Warn("Bad XML", "error", err)
-						}
-					} else {
-						slog.Warn("Error reading response")
-					}
-				}
-			}
+		for _, igd := range <-DiscoverAllIGDsWithOptions(opts) {
+			ret <- igd
 		}
 
 		// If we get here we did not find an IGD or have already passed the
@@ -136,12 +152,149 @@ func DiscoverIGD() (ret chan *IGD) {
 	return
 }
 
+// This function returns a buffered channel which will be sent a single slice
+// containing every IGD found on every interface with an IP address in the
+// private network range, sorted from best to worst Rank. The slice is empty if
+// no IGD could be found.
+//
+// Unlike DiscoverIGD() this lets callers inspect the ranking of all candidates
+// before committing to one.
+func DiscoverAllIGDs() (ret chan []*IGD) {
+	return DiscoverAllIGDsWithOptions(nil)
+}
+
+// This function behaves as DiscoverAllIGDs() but only searches on the local
+// interfaces allowed by the passed options.
+func DiscoverAllIGDsWithOptions(opts *DiscoveryOptions) (ret chan []*IGD) {
+	ret = make(chan []*IGD, 1)
+
+	go func() {
+		// For each and every local address allowed by opts, by default those
+		// in the private network range and IPv6 link-local ones, we search
+		// concurrently as each search may take several seconds to time out
+		locals := localInterfaces(opts)
+		slog.Debug("Found usable network interfaces", "count", len(locals))
+		found := make([]*IGD, len(locals))
+		var wg sync.WaitGroup
+		for i := range locals {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				// Use SSDP to search for a UPnP-enabled IGD
+				descURL, ok := discoverIGDDescriptionURL(locals[i])
+
+				if ok {
+					found[i], _ = newIGD(descURL, locals[i], opts.strict())
+				}
+			}()
+		}
+		wg.Wait()
+
+		var igds []*IGD
+		for _, igd := range found {
+			if igd != nil {
+				igds = append(igds, igd)
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), rankTimeout)
+		rankIGDs(ctx, igds)
+		cancel()
+		ret <- igds
+		close(ret)
+	}()
+	return
+}
+
+// This function fetches the description XML found at descURL and wraps the
+// connection control service it describes into an IGD bound to the passed
+// local interface. Documents are parsed leniently unless strict is set.
+func newIGD(descURL *url.URL, local localInterface, strict bool) (*IGD, bool) {
+	// We go fetch its description XML
+	resp, err := http.Get(descURL.String())
+	if err != nil {
+		slog.Warn("Failed to fetch description", "url", descURL, "error", err)
+		return nil, false
+	}
+	// We got something back, lets not leak it
+	defer resp.Body.Close()
+	// We read in the whole description into memory We might envisage at a
+	// later date putting an upperbound on the buffer, however there is no
+	// risk of buffer overflow, so it is a low priority
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		slog.Warn("Error reading response")
+		return nil, false
+	}
+	slog.Debug("Description XML", "content", string(body))
+	// Parse the XML and extract relevant information
+	description, err := parseDeviceDescription(body, strict)
+	if err != nil {
+		slog.Warn("Bad XML", "error", err)
+		return nil, false
+	}
+	// It worked, lets now try and wrap it in an igd struct
+	igd := IGD{description: description, descURL: descURL, link: local.iface,
+		strict: strict}
+
+	// Routers may have several connection services, only one of which
+	// actually connected
+	ctx, cancel := context.WithTimeout(context.Background(), selectTimeout)
+	err = igd.selectConnectedService(ctx)
+	cancel()
+	if err != nil {
+		slog.Warn("Bad control URL", "error", err)
+		return nil, false
+	}
+	// We now add the local binding address to enable the simple
+	// AddLocalPortRedirection method
+	igd.iface = local.addr.IP
+	// Port mappings being IPv4 only, IGDs found over IPv6 map ports to an IPv4
+	// address of the same interface
+	igd.internalClient = local.addr.IP.To4()
+	if igd.internalClient == nil && local.iface != nil {
+		if addrs, err := local.iface.Addrs(); err == nil {
+			igd.internalClient = ipv4Addr(addrs)
+		}
+	}
+
+	// IPv6 firewall control is optional and lives alongside the connection
+	// service, we only keep track of it if present
+	if _, firewallURL, err := description.serviceControlURL(descURL,
+		firewallTypeString); err == nil {
+		setZone(firewallURL, local.iface)
+		igd.firewallURL = firewallURL
+	}
+
+	// Finally we note where the IGD was found so that it may be ranked
+	// against others
+	igd.rank.host = hostIP(descURL)
+	return &igd, true
+}
+
+// This type describes the status of the WAN connection of an IGD as reported
+// by GetStatusInfo, along with its external IP address when connected.
 type ConnectionStatus struct {
+	// Equivalent to State == ConnectionConnected
 	Connected bool
 	IP        net.IP
+	State     ConnectionState
+	// How long the connection has been up, zero unless connected
+	Uptime time.Duration
+	// The reason the last connection attempt failed, e.g.
+	// ERROR_AUTHENTICATION_FAILURE, or ERROR_NONE
+	LastError string
 }
 
-// This method fetches the status of the IGD.
+func (self *ConnectionStatus) String() string {
+	if self.Connected {
+		return fmt.Sprint(self.State, " ", self.IP, " for ", self.Uptime)
+	}
+	return fmt.Sprint(self.State, " (", self.LastError, ")")
+}
+
+// This method fetches the status of the IGD, and its external IP address if it
+// is connected.
 //
 // Errors are indicated by the channel closing before a ConnectionStatus is
 // returned. Listeners should therefore check at the very least for nil, better
@@ -156,32 +309,49 @@ func (self *IGD) GetConnectionStatus() (ret chan *ConnectionStatus) {
 	// We go do the work in a separate goroutine, the closure has access to the
 	// channel we just instanciated so we will be able to manipulate it.
 	go func() {
-		x, ok := self.soapRequest("GetStatusInfo", statusRequestStringReader(self.upnptype))
-		if ok && strings.EqualFold(x.Body.Status.NewConnectionStatus, "Connected") {
-			y, ok := self.soapRequest("GetExternalIPAddress", externalIPRequestStringReader(self.upnptype))
-
-			if ok {
-				ipString := y.Body.IP.NewExternalIPAddress
-				ip := net.ParseIP(ipString)
-				if ip != nil {
-					ret <- &ConnectionStatus{true, ip}
-					return
-				} else {
-					slog.Warn("Failed to parse IP string", "ip", ipString)
-				}
-			} else {
-				slog.Warn("Failed to get IP address after establishing the connection was ok")
-			}
-		} else if ok && strings.EqualFold(x.Body.Status.NewConnectionStatus, "Disconnected") {
-			ret <- &ConnectionStatus{false, nil}
+		defer close(ret)
+		if status, ok := self.connectionStatus(context.Background()); ok {
+			ret <- status
 		}
-		close(ret)
 	}()
 
 	// We immediately return the channel to the caller
 	return
 }
 
+// This method does the work of GetConnectionStatus() within the passed
+// context, which allows bounding the time it takes.
+func (self *IGD) connectionStatus(ctx context.Context) (*ConnectionStatus, bool) {
+	x, ok := self.soapRequest(ctx, "GetStatusInfo", struct{}{})
+	if !ok {
+		return nil, false
+	}
+	status := ConnectionStatus{
+		State:     ParseConnectionState(x.Body.Status.NewConnectionStatus),
+		LastError: strings.TrimSpace(x.Body.Status.NewLastConnectionError),
+	}
+	status.Connected = status.State == ConnectionConnected
+	if !status.Connected {
+		return &status, true
+	}
+
+	if uptime, err := strconv.ParseUint(strings.TrimSpace(x.Body.Status.NewUptime), 10, 32); err == nil {
+		status.Uptime = time.Duration(uptime) * time.Second
+	}
+	y, ok := self.soapRequest(ctx, "GetExternalIPAddress", struct{}{})
+	if !ok {
+		slog.Warn("Failed to get IP address after establishing the connection was ok")
+		return nil, false
+	}
+	ipString := y.Body.IP.NewExternalIPAddress
+	status.IP = net.ParseIP(strings.TrimSpace(ipString))
+	if status.IP == nil {
+		slog.Warn("Failed to parse IP string", "ip", ipString)
+		return nil, false
+	}
+	return &status, true
+}
+
 // This method creates a port mapping on the IGD with internal, external ports
 // and protocol respectively equal to the passed port argument (bis) and
 // protocol
@@ -192,21 +362,34 @@ func (self *IGD) GetConnectionStatus() (ret chan *ConnectionStatus) {
 //
 // NOTA BENE the channel closes after a successive PortMapping has been send on
 // it, in order to not leak resources.
+//
+// The mapping is made to an IPv4 address of the interface the IGD was found
+// on, it therefore fails for IGDs found over IPv6 on interfaces without one.
 func (self *IGD) AddLocalPortRedirection(port uint16, proto protocol) (ret chan *PortMapping) {
 	ret = make(chan *PortMapping)
 
 	go func() {
-		description := fmt.Sprintf("goupnp %s %d %s", self.iface, port, proto)
-		_, ok := self.soapRequest("AddPortMapping",
-			createPortMappingStringReader(self.upnptype, port,
-				proto, self.iface, description))
+		if self.internalClient == nil {
+			slog.Warn("No IPv4 address to map the port to", "iface", self.iface)
+			close(ret)
+			return
+		}
+		description := fmt.Sprintf("goupnp %s %d %s", self.internalClient, port, proto)
+		_, ok := self.soapRequest(context.Background(), "AddPortMapping", &addPortMappingRequest{
+			NewExternalPort:           port,
+			NewProtocol:               proto.String(),
+			NewInternalPort:           port,
+			NewInternalClient:         self.internalClient.String(),
+			NewEnabled:                true,
+			NewPortMappingDescription: description,
+		})
 		if ok {
 			portMapping := PortMapping{
 				InternalPort: port,
 				ExternalPort: port,
 				Enabled:      true,
 				Description:  description,
-				InternalHost: self.iface,
+				InternalHost: self.internalClient,
 				Protocol:     proto,
 			}
 
@@ -218,16 +401,26 @@ func (self *IGD) AddLocalPortRedirection(port uint16, proto protocol) (ret chan
 	return
 }
 
-// BUG(nhelke): NOT IMPLEMENTED — ALWAYS RETURNS ERROR
+// This method deletes each of the passed port mappings from the IGD. Only
+// their ExternalPort and Protocol are taken into account.
 //
-// Please feel free to submit a pull request to
-// https://github.com/nhelke/goupnpc and I will be sure to merge it.
+// The channel is sent one value per port mapping, in order, nil on success and
+// an error otherwise, before being closed.
 func (self *IGD) DeletePortRedirection(portMappings ...*PortMapping) (ret chan error) {
-	ret = make(chan error)
+	ret = make(chan error, len(portMappings))
 	go func() {
-		ret <- errors.New("Sorry, I haven't implemented this yet. " +
-			"Feel free to submit a pull request to github.com/nhelke/goupnpc " +
-			"and I will be sure to merge it.")
+		for _, portMapping := range portMappings {
+			_, ok := self.soapRequest(context.Background(), "DeletePortMapping",
+				&deletePortMappingRequest{
+					NewExternalPort: portMapping.ExternalPort,
+					NewProtocol:     portMapping.Protocol.String(),
+				})
+			if ok {
+				ret <- nil
+			} else {
+				ret <- fmt.Errorf("Failed to delete port mapping %v", portMapping)
+			}
+		}
 		close(ret)
 	}()
 	return ret
@@ -246,8 +439,8 @@ func (self *IGD) ListRedirections() (ret chan *PortMapping) {
 			x  *soapEnvelope
 		)
 		for ; ; i++ {
-			x, ok = self.soapRequest("GetGenericPortMappingEntry",
-				portMappingRequestStringReader(self.upnptype, i))
+			x, ok = self.soapRequest(context.Background(), "GetGenericPortMappingEntry",
+				&getGenericPortMappingEntryRequest{NewPortMappingIndex: i})
 			if ok {
 				portMapping := PortMapping{
 					InternalPort: x.Body.PortMapping.InternalPort,
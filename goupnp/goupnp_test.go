@@ -30,3 +30,32 @@ func TestIsPrivateIPAddress(t *testing.T) {
 		}
 	}
 }
+
+func TestAddLocalPortRedirection(t *testing.T) {
+	var clients []string
+	handler := newSOAPTestHandler(t, func(action string, args map[string]string) map[string]string {
+		if action != "AddPortMapping" {
+			return nil
+		}
+		clients = append(clients, args["NewInternalClient"])
+		return map[string]string{}
+	})
+
+	igd := newTestIGDWith(t, loopbackInterface, belkinDescription, nil, handler)
+	mapping, ok := <-igd.AddLocalPortRedirection(8080, TCP)
+	if !ok || !mapping.InternalHost.Equal(net.IPv4(127, 0, 0, 1)) {
+		t.Errorf("Port mapping created as %v", mapping)
+	}
+
+	// Found over IPv6 on an interface without any IPv4 address
+	igd = newTestIGDWith(t, localInterface{
+		addr: &net.UDPAddr{IP: net.ParseIP("fe80::1"), Zone: "eth0"},
+	}, belkinDescription, nil, handler)
+	if mapping, ok := <-igd.AddLocalPortRedirection(8080, TCP); ok {
+		t.Errorf("Port mapping created as %v for a link-local address", mapping)
+	}
+
+	if len(clients) != 1 || clients[0] != "127.0.0.1" {
+		t.Errorf("Port mappings requested for %v", clients)
+	}
+}
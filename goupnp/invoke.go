@@ -0,0 +1,195 @@
+package goupnp
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"log/slog"
+)
+
+// This type describes the error an IGD reports by way of a SOAP fault when an
+// action fails, e.g. 714 NoSuchEntryInArray or 718 ConflictInMappingEntry.
+type UPnPError struct {
+	Code        int
+	Description string
+}
+
+func (self *UPnPError) Error() string {
+	return fmt.Sprintf("UPnP error %d: %s", self.Code, self.Description)
+}
+
+type soapFault struct {
+	FaultCode   string `xml:"faultcode"`
+	FaultString string `xml:"faultstring"`
+	Detail      struct {
+		UPnPError struct {
+			ErrorCode        int    `xml:"errorCode"`
+			ErrorDescription string `xml:"errorDescription"`
+		} `xml:"UPnPError"`
+	} `xml:"detail"`
+}
+
+// This function returns the UPnPError described by the SOAP fault contained in
+// the passed response body, or nil if it contains none.
+func parseSOAPFault(body []byte) *UPnPError {
+	var x struct {
+		Body struct {
+			Fault *soapFault `xml:"Fault"`
+		} `xml:"Body"`
+	}
+	// Faults only ever serve to report errors, hence are always parsed
+	// leniently
+	if decodeXML(body, &x, false) != nil || x.Body.Fault == nil {
+		return nil
+	}
+	fault := x.Body.Fault
+	if fault.Detail.UPnPError.ErrorCode == 0 {
+		return &UPnPError{Description: fault.FaultString}
+	}
+	return &UPnPError{
+		Code:        fault.Detail.UPnPError.ErrorCode,
+		Description: fault.Detail.UPnPError.ErrorDescription,
+	}
+}
+
+// This function returns the out-arguments of the action response contained in
+// the passed SOAP envelope, that is the text of every child of the first
+// element of the Body, see newXMLDecoder() for strict.
+func parseSOAPResponse(body []byte, strict bool) (map[string]string, error) {
+	decoder := newXMLDecoder(body, strict)
+	ret := make(map[string]string)
+	var (
+		depth int
+		name  string
+		value strings.Builder
+	)
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			if depth == 0 && len(ret) > 0 {
+				return ret, nil
+			}
+			return nil, fmt.Errorf("Malformed SOAP response: %w", err)
+		}
+		switch token := token.(type) {
+		case xml.StartElement:
+			depth++
+			if depth == 1 {
+				err := checkNamespace(token.Name, soapEnvelopeNamespace, strict)
+				if err != nil {
+					return nil, fmt.Errorf("Malformed SOAP response: %w", err)
+				}
+			}
+			// Envelope > Body > ActionResponse > argument, anything else such
+			// as a SOAP Header is skipped
+			if depth == 2 && token.Name.Local != "Body" {
+				if err := decoder.Skip(); err != nil {
+					return nil, fmt.Errorf("Malformed SOAP response: %w", err)
+				}
+				depth--
+			}
+			if depth == 4 {
+				name = token.Name.Local
+				value.Reset()
+			}
+		case xml.CharData:
+			if depth == 4 {
+				value.Write(token)
+			}
+		case xml.EndElement:
+			if depth == 4 {
+				ret[name] = value.String()
+			}
+			depth--
+			// We are done as soon as the action response element closes
+			if depth == 2 {
+				return ret, nil
+			}
+		}
+	}
+}
+
+// This function returns true if and only if the passed string may be used as
+// the name of an XML element without any escaping.
+func isXMLName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, r := range name {
+		switch {
+		case r == '_' || 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z':
+		case i > 0 && (r == '-' || r == '.' || '0' <= r && r <= '9'):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// This method invokes the passed action of the passed service of the IGD,
+// which must be one of those found in its Description(), and returns every
+// out-argument of the response. It allows calling vendor-specific and less
+// common actions this package does not wrap.
+//
+// Arguments are sent in the order the SCPD of the service lists them, which
+// some IGDs require, falling back to alphabetical order for arguments the
+// SCPD does not list or when it cannot be fetched. Failures reported by the
+// IGD are returned as *UPnPError.
+func (self *IGD) Invoke(ctx context.Context, service *Service, action string,
+	args map[string]string) (map[string]string, error) {
+	controlURL, err := self.resolveURL(service.ControlURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var arguments soapArguments
+	for _, name := range self.argumentOrder(ctx, service, action, args) {
+		arguments = append(arguments, soapArgument{name, args[name]})
+	}
+	envelope, err := marshalSOAP(service.ServiceType, action, arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := postSOAP(ctx, controlURL, service.ServiceType, action,
+		bytes.NewReader(envelope))
+	if err != nil {
+		return nil, err
+	}
+	return parseSOAPResponse(body, self.strict)
+}
+
+// This method returns the names of the passed arguments in the order they
+// should be sent to the passed action of the passed service, fetching its
+// SCPD within ctx if there are any.
+func (self *IGD) argumentOrder(ctx context.Context, service *Service, action string,
+	args map[string]string) (names []string) {
+	if len(args) == 0 {
+		return nil
+	}
+	if scpd, err := self.scpd(ctx, service); err == nil {
+		if a := scpd.Action(action); a != nil {
+			for _, argument := range a.InArguments() {
+				if _, ok := args[argument.Name]; ok {
+					names = append(names, argument.Name)
+				}
+			}
+		}
+	} else {
+		slog.Debug("No SCPD to order arguments", "service", service.ServiceType, "error", err)
+	}
+
+	var rest []string
+	for name := range args {
+		if !slices.Contains(names, name) {
+			rest = append(rest, name)
+		}
+	}
+	sort.Strings(rest)
+	return append(names, rest...)
+}
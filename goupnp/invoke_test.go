@@ -0,0 +1,160 @@
+package goupnp
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const exampleSOAPFault = `<?xml version="1.0"?>
+<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/"
+s:encodingStyle="http://schemas.xmlsoap.org/soap/encoding/"><s:Body>
+<s:Fault><faultcode>s:Client</faultcode><faultstring>UPnPError</faultstring>
+<detail><UPnPError xmlns="urn:schemas-upnp-org:control-1-0">
+<errorCode>714</errorCode><errorDescription>NoSuchEntryInArray</errorDescription>
+</UPnPError></detail></s:Fault></s:Body></s:Envelope>
+`
+
+func TestSOAPResponseParsing(t *testing.T) {
+	args, err := parseSOAPResponse([]byte(exampleBelkinPortMappingResponse), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if args["NewExternalPort"] != "5900" || args["NewInternalClient"] != "192.168.2.5" ||
+		args["NewRemoteHost"] != "" || len(args) != 8 {
+		t.Errorf("Out-arguments incorrectly parsed as %v", args)
+	}
+
+	upnpErr := parseSOAPFault([]byte(exampleSOAPFault))
+	if upnpErr == nil || upnpErr.Code != 714 || upnpErr.Description != "NoSuchEntryInArray" {
+		t.Errorf("Fault incorrectly parsed as %v", upnpErr)
+	}
+}
+
+func TestInvoke(t *testing.T) {
+	// The connection service checks the request
+	documents := map[string]string{"/upnp/service/WANIPCn.xml": exampleWANIPConnectionSCPD}
+	igd := newTestIGDWith(t, loopbackInterface, belkinDescription, documents,
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			if r.Header.Get("SOAPAction") != `"urn:schemas-upnp-org:service:WANIPConnection:1#DeletePortMapping"` {
+				t.Errorf("Bad SOAPAction header %v", r.Header.Get("SOAPAction"))
+			}
+			// Arguments must follow the SCPD order and be escaped
+			expected := `<u:DeletePortMapping xmlns:u="urn:schemas-upnp-org:service:WANIPConnection:1">` +
+				`<NewRemoteHost>a&lt;b&amp;c</NewRemoteHost><NewExternalPort>5900</NewExternalPort>` +
+				`<NewProtocol>TCP</NewProtocol><X_Vendor>1</X_Vendor></u:DeletePortMapping>`
+			if !strings.Contains(string(body), expected) {
+				t.Errorf("Bad request body %s", body)
+			}
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(exampleSOAPFault))
+		}))
+	service := igd.ConnectionService()
+
+	_, err := igd.Invoke(context.Background(), service, "DeletePortMapping",
+		map[string]string{
+			"X_Vendor":        "1",
+			"NewProtocol":     "TCP",
+			"NewExternalPort": "5900",
+			"NewRemoteHost":   "a<b&c",
+		})
+	var upnpErr *UPnPError
+	if !errors.As(err, &upnpErr) || upnpErr.Code != 714 {
+		t.Errorf("Expected UPnP error 714, got %v", err)
+	}
+
+	if _, err = igd.Invoke(context.Background(), service, "Bad<Action", nil); err == nil {
+		t.Error("Invalid action name accepted")
+	}
+}
+
+func TestInvokeSCPDFailures(t *testing.T) {
+	var fetches atomic.Int32
+	soap := newSOAPTestHandler(t, func(action string, args map[string]string) map[string]string {
+		return map[string]string{}
+	})
+	igd := newTestIGDWith(t, loopbackInterface, belkinDescription, nil,
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == "POST" {
+				soap.ServeHTTP(w, r)
+				return
+			}
+			fetches.Add(1)
+			w.WriteHeader(http.StatusNotFound)
+		}))
+	service := igd.ConnectionService()
+	args := map[string]string{"NewRemoteHost": "", "NewExternalPort": "5900",
+		"NewProtocol": "TCP"}
+
+	// Without arguments there is nothing to order
+	if _, err := igd.Invoke(context.Background(), service, "GetStatusInfo", nil); err != nil {
+		t.Fatal(err)
+	}
+	if n := fetches.Load(); n != 0 {
+		t.Errorf("SCPD fetched %d times for an action without arguments", n)
+	}
+
+	// A missing SCPD is only fetched once
+	for range 2 {
+		if _, err := igd.Invoke(context.Background(), service, "DeletePortMapping", args); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := fetches.Load(); n != 1 {
+		t.Errorf("Missing SCPD fetched %d times", n)
+	}
+
+	// A stalled SCPD does not outlast the context
+	stop := make(chan struct{})
+	defer close(stop)
+	igd = newTestIGDWith(t, loopbackInterface, belkinDescription, nil,
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-stop
+		}))
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	igd.Invoke(ctx, igd.ConnectionService(), "DeletePortMapping", args)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Invoke took %v despite the deadline", elapsed)
+	}
+}
+
+// This function returns a handler playing every service of an IGD, answering
+// each action with the out-arguments handle returns for it and its
+// in-arguments, or with a SOAP fault if it returns nil.
+func newSOAPTestHandler(t *testing.T,
+	handle func(action string, args map[string]string) map[string]string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Such as SCPDs
+		if r.Method != "POST" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		serviceType, action, _ := strings.Cut(strings.Trim(r.Header.Get("SOAPAction"), `"`), "#")
+		body, _ := io.ReadAll(r.Body)
+		args, err := parseSOAPResponse(body, true)
+		if err != nil {
+			t.Errorf("%s: %v", action, err)
+		}
+
+		out := handle(action, args)
+		if out == nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(exampleSOAPFault))
+			return
+		}
+		var arguments soapArguments
+		for name, value := range out {
+			arguments = append(arguments, soapArgument{name, value})
+		}
+		response, _ := marshalSOAP(serviceType, action+"Response", arguments)
+		w.Write(response)
+	})
+}
@@ -0,0 +1,113 @@
+package goupnp
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+
+	"log/slog"
+)
+
+// The error returned by the LAN configuration methods when the IGD does not
+// implement the LANHostConfigManagement service.
+var ErrNoLANHostConfigManagement = errors.New("IGD does not provide LANHostConfigManagement")
+
+// This type describes the configuration of the LAN side of an IGD, as handed
+// out to its hosts by its DHCP server.
+type LANHostConfig struct {
+	// Whether the DHCP server of the IGD may be configured over UPnP
+	DHCPServerConfigurable bool
+	SubnetMask             net.IPMask
+	IPRouters              []net.IP
+	DomainName             string
+	// The range of addresses the DHCP server hands out
+	MinAddress net.IP
+	MaxAddress net.IP
+	DNSServers []net.IP
+}
+
+// This method returns a client for the LANHostConfigManagement service of the
+// IGD, which the specification places in its LANDevice. The first one found is
+// returned on IGDs with several LANDevices.
+func (self *IGD) LANHostConfigManagement() (*LANHostConfigManagement1, error) {
+	_, service := self.description.Device.FindService(LANHostConfigManagement1ServiceType)
+	if service == nil {
+		return nil, ErrNoLANHostConfigManagement
+	}
+	return &LANHostConfigManagement1{igd: self, Service: service}, nil
+}
+
+// This method fetches the configuration of the LAN side of the IGD. IGDs
+// rarely implement every action of LANHostConfigManagement, hence fields are
+// left zero for the actions the IGD fails, and an error is only returned if
+// every one of them fails.
+func (self *IGD) GetLANHostConfig(ctx context.Context) (*LANHostConfig, error) {
+	client, err := self.LANHostConfigManagement()
+	if err != nil {
+		return nil, err
+	}
+	var (
+		config LANHostConfig
+		errs   []error
+	)
+	if resp, err := client.GetDHCPServerConfigurable(ctx); err == nil {
+		config.DHCPServerConfigurable = resp.NewDHCPServerConfigurable
+	} else {
+		errs = append(errs, err)
+	}
+	if resp, err := client.GetSubnetMask(ctx); err == nil {
+		if ip := net.ParseIP(strings.TrimSpace(resp.NewSubnetMask)).To4(); ip != nil {
+			config.SubnetMask = net.IPMask(ip)
+		}
+	} else {
+		errs = append(errs, err)
+	}
+	if resp, err := client.GetIPRoutersList(ctx); err == nil {
+		config.IPRouters = parseIPList(resp.NewIPRouters)
+	} else {
+		errs = append(errs, err)
+	}
+	if resp, err := client.GetDomainName(ctx); err == nil {
+		config.DomainName = resp.NewDomainName
+	} else {
+		errs = append(errs, err)
+	}
+	if resp, err := client.GetAddressRange(ctx); err == nil {
+		config.MinAddress = net.ParseIP(strings.TrimSpace(resp.NewMinAddress))
+		config.MaxAddress = net.ParseIP(strings.TrimSpace(resp.NewMaxAddress))
+	} else {
+		errs = append(errs, err)
+	}
+	if resp, err := client.GetDNSServers(ctx); err == nil {
+		config.DNSServers = parseIPList(resp.NewDNSServers)
+	} else {
+		errs = append(errs, err)
+	}
+
+	if len(errs) == 6 {
+		return nil, errors.Join(errs...)
+	}
+	for _, err := range errs {
+		slog.Debug("While fetching LAN host config", "error", err)
+	}
+	return &config, nil
+}
+
+// This function parses the passed comma-separated list of IP addresses, as
+// found in the IPRouters and DNSServers state variables, skipping any entry
+// which is not an IP address.
+func parseIPList(str string) (ret []net.IP) {
+	for _, field := range strings.Split(str, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		if ip := net.ParseIP(field); ip != nil {
+			ret = append(ret, ip)
+		} else {
+			slog.Debug("Not an IP address", "address", field)
+		}
+	}
+	return
+}
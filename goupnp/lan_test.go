@@ -0,0 +1,65 @@
+package goupnp
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+)
+
+func TestGetLANHostConfig(t *testing.T) {
+	description := strings.Replace(belkinDescription, "\t\t</deviceList>\n\t\t<presentationURL>", `
+			<device>
+				<deviceType>urn:schemas-upnp-org:device:LANDevice:1</deviceType>
+				<serviceList><service>
+					<serviceType>urn:schemas-upnp-org:service:LANHostConfigManagement:1</serviceType>
+					<serviceId>urn:upnp-org:serviceId:LANHostCfg1</serviceId>
+					<controlURL>/upnp/control/LANHostCfg1</controlURL>
+				</service></serviceList>
+			</device>
+		</deviceList>
+		<presentationURL>`, 1)
+	igd := newTestIGDWith(t, loopbackInterface, description, nil, newSOAPTestHandler(t, func(action string, args map[string]string) map[string]string {
+		switch action {
+		case "GetDHCPServerConfigurable":
+			return map[string]string{"NewDHCPServerConfigurable": "1"}
+		case "GetSubnetMask":
+			return map[string]string{"NewSubnetMask": "255.255.255.0"}
+		case "GetIPRoutersList":
+			return map[string]string{"NewIPRouters": "192.168.2.1"}
+		case "GetAddressRange":
+			return map[string]string{
+				"NewMinAddress": "192.168.2.100",
+				"NewMaxAddress": "192.168.2.199",
+			}
+		case "GetDNSServers":
+			return map[string]string{"NewDNSServers": "192.168.2.1, 9.9.9.9,bogus"}
+		}
+		// GetDomainName is not implemented
+		return nil
+	}))
+
+	config, err := igd.GetLANHostConfig(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !config.DHCPServerConfigurable || config.SubnetMask.String() != "ffffff00" ||
+		config.DomainName != "" ||
+		!config.MinAddress.Equal(net.IPv4(192, 168, 2, 100)) ||
+		!config.MaxAddress.Equal(net.IPv4(192, 168, 2, 199)) {
+		t.Errorf("LAN host config incorrectly parsed as %+v", config)
+	}
+	if len(config.IPRouters) != 1 || !config.IPRouters[0].Equal(net.IPv4(192, 168, 2, 1)) {
+		t.Errorf("IP routers incorrectly parsed as %v", config.IPRouters)
+	}
+	if len(config.DNSServers) != 2 || !config.DNSServers[1].Equal(net.IPv4(9, 9, 9, 9)) {
+		t.Errorf("DNS servers incorrectly parsed as %v", config.DNSServers)
+	}
+}
+
+func TestNoLANHostConfigManagement(t *testing.T) {
+	igd := newTestIGD(t, nil)
+	if _, err := igd.GetLANHostConfig(context.Background()); err != ErrNoLANHostConfigManagement {
+		t.Errorf("Expected ErrNoLANHostConfigManagement, got %v", err)
+	}
+}
@@ -0,0 +1,135 @@
+package goupnp
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// The documents served by IGDs in the wild regularly fall short of the UPnP
+// and SOAP specifications: missing or misspelt namespaces, byte order marks,
+// ISO-8859-1 content, blank lines or junk before the XML declaration. Unless
+// DiscoveryOptions.Strict is set these are all tolerated, elements being
+// matched on their local name only.
+
+// This function returns a decoder for the passed document, leniently unless
+// strict is set.
+func newXMLDecoder(body []byte, strict bool) *xml.Decoder {
+	if strict {
+		return xml.NewDecoder(bytes.NewReader(body))
+	}
+	decoder := xml.NewDecoder(bytes.NewReader(sanitizeXML(body)))
+	decoder.Strict = false
+	decoder.CharsetReader = charsetReader
+	return decoder
+}
+
+// This function decodes the passed document into v, see newXMLDecoder().
+func decodeXML(body []byte, v any, strict bool) error {
+	return newXMLDecoder(body, strict).Decode(v)
+}
+
+// This function returns an error if strict is set and the passed element is
+// not in the namespace the specifications require of it.
+func checkNamespace(name xml.Name, namespace string, strict bool) error {
+	if strict && name.Space != namespace {
+		return fmt.Errorf("Element %s in namespace %q rather than %q",
+			name.Local, name.Space, namespace)
+	}
+	return nil
+}
+
+// This function strips anything preceding the first element or XML
+// declaration of the passed document, including byte order marks, and
+// converts it when it is not valid UTF-8, from windows-1252 if its XML
+// declaration says so and from ISO-8859-1 otherwise, whatever else it claims.
+func sanitizeXML(body []byte) []byte {
+	if i := bytes.IndexByte(body, '<'); i > 0 {
+		body = body[i:]
+	}
+	if utf8.Valid(body) {
+		return body
+	}
+
+	// The declaration no longer holds once converted, the decoder would
+	// otherwise attempt to convert the document a second time
+	convert := latin1ToUTF8
+	if bytes.HasPrefix(body, []byte("<?xml")) {
+		if i := bytes.Index(body, []byte("?>")); i >= 0 {
+			switch declaredEncoding(body[:i]) {
+			case "windows-1252", "cp1252":
+				convert = windows1252ToUTF8
+			}
+			body = body[i+2:]
+		}
+	}
+	return convert(body)
+}
+
+// This function returns the lowercased encoding named by the passed XML
+// declaration, or "" if it names none.
+func declaredEncoding(declaration []byte) string {
+	_, rest, ok := bytes.Cut(declaration, []byte("encoding"))
+	if !ok {
+		return ""
+	}
+	rest = bytes.TrimLeft(rest, " \t\r\n=")
+	if len(rest) == 0 || rest[0] != '"' && rest[0] != '\'' {
+		return ""
+	}
+	encoding, _, _ := bytes.Cut(rest[1:], rest[:1])
+	return strings.ToLower(strings.TrimSpace(string(encoding)))
+}
+
+func latin1ToUTF8(body []byte) []byte {
+	ret := make([]byte, 0, len(body)*2)
+	for _, b := range body {
+		ret = utf8.AppendRune(ret, rune(b))
+	}
+	return ret
+}
+
+// The characters of windows-1252 bytes 0x80 to 0x9F, where it differs from
+// ISO-8859-1. The five bytes it leaves undefined are kept as C1 controls.
+var windows1252 = [32]rune{
+	'€', '\u0081', '‚', 'ƒ', '„', '…', '†', '‡', 'ˆ', '‰', 'Š', '‹', 'Œ', '\u008d', 'Ž', '\u008f',
+	'\u0090', '‘', '’', '“', '”', '•', '–', '—', '˜', '™', 'š', '›', 'œ', '\u009d', 'ž', 'Ÿ',
+}
+
+func windows1252ToUTF8(body []byte) []byte {
+	ret := make([]byte, 0, len(body)*2)
+	for _, b := range body {
+		if 0x80 <= b && b <= 0x9f {
+			ret = utf8.AppendRune(ret, windows1252[b-0x80])
+		} else {
+			ret = utf8.AppendRune(ret, rune(b))
+		}
+	}
+	return ret
+}
+
+// This function converts documents declaring one of the few non-UTF-8
+// encodings found on IGDs, all of which are supersets of ASCII: ISO-8859-1
+// and windows-1252, which only differ in bytes 0x80 to 0x9F.
+func charsetReader(charset string, input io.Reader) (io.Reader, error) {
+	var convert func([]byte) []byte
+	switch strings.ToLower(strings.TrimSpace(charset)) {
+	case "utf8":
+		return input, nil
+	case "iso-8859-1", "iso8859-1", "iso_8859-1", "latin1", "l1",
+		"us-ascii", "ascii":
+		convert = latin1ToUTF8
+	case "windows-1252", "cp1252":
+		convert = windows1252ToUTF8
+	default:
+		return nil, fmt.Errorf("Unsupported charset %q", charset)
+	}
+	body, err := io.ReadAll(input)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(convert(body)), nil
+}
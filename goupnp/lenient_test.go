@@ -0,0 +1,132 @@
+package goupnp
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Every document of testdata/broken was modelled after one served by an IGD
+// in the wild and must be parsed in lenient mode. Strict mode must reject
+// those encoding/xml does not already tolerate by itself.
+var strictlyAcceptedDocuments = map[string]bool{
+	"description-bom.xml":             true,
+	"description-leading-garbage.xml": true,
+	"soap-bom.xml":                    true,
+}
+
+func TestBrokenDocuments(t *testing.T) {
+	paths, err := filepath.Glob("testdata/broken/*.xml")
+	if err != nil || len(paths) == 0 {
+		t.Fatal("No broken documents found", err)
+	}
+	for _, path := range paths {
+		body, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		name := filepath.Base(path)
+		switch {
+		case strings.HasPrefix(name, "description-"):
+			testBrokenDescription(t, name, body)
+		case strings.HasPrefix(name, "scpd-"):
+			testBrokenSCPD(t, name, body)
+		case strings.HasPrefix(name, "soap-"):
+			testBrokenSOAP(t, name, body)
+		default:
+			t.Errorf("%s: unknown kind of document", name)
+		}
+	}
+}
+
+func testBrokenDescription(t *testing.T, name string, body []byte) {
+	_, err := parseDeviceDescription(body, true)
+	if err == nil && !strictlyAcceptedDocuments[name] {
+		t.Errorf("%s: accepted in strict mode", name)
+	}
+	x, err := ParseDeviceDescription(body)
+	if err != nil {
+		t.Errorf("%s: %v", name, err)
+		return
+	}
+	expected := "Routeur Déco"
+	if name == "description-unknown-entity.xml" {
+		// The entity is kept as is rather than failing the whole document
+		expected = "Routeur&nbsp;Déco"
+	}
+	if x.Device.FriendlyName != expected {
+		t.Errorf("%s: friendlyName parsed as %q", name, x.Device.FriendlyName)
+	}
+	if name == "description-windows1252.xml" &&
+		x.Device.Manufacturer != "Example € “quoted”" {
+		t.Errorf("%s: manufacturer parsed as %q", name, x.Device.Manufacturer)
+	}
+	if _, service := x.Device.FindService(connectionTypeStringWANIP); service == nil ||
+		service.ControlURL != "/ctl/IPConn" {
+		t.Errorf("%s: connection service parsed as %+v", name, service)
+	}
+}
+
+func testBrokenSCPD(t *testing.T, name string, body []byte) {
+	_, err := parseSCPD(body, true)
+	if err == nil && !strictlyAcceptedDocuments[name] {
+		t.Errorf("%s: accepted in strict mode", name)
+	}
+	x, err := ParseSCPD(body)
+	if err != nil {
+		t.Errorf("%s: %v", name, err)
+		return
+	}
+	if x.Action("GetStatusInfo") == nil || x.StateVariable("ConnectionStatus") == nil {
+		t.Errorf("%s: incorrectly parsed as %+v", name, x)
+	}
+}
+
+func testBrokenSOAP(t *testing.T, name string, body []byte) {
+	_, err := parseSOAPResponse(body, true)
+	if err == nil && !strictlyAcceptedDocuments[name] {
+		t.Errorf("%s: accepted in strict mode", name)
+	}
+	args, err := parseSOAPResponse(body, false)
+	if err != nil {
+		t.Errorf("%s: %v", name, err)
+	} else if args["NewConnectionStatus"] != "Connected" || args["NewUptime"] != "3600" {
+		t.Errorf("%s: arguments parsed as %v", name, args)
+	}
+
+	var x soapEnvelope
+	if err := decodeXML(body, &x, false); err != nil {
+		t.Errorf("%s: %v", name, err)
+	} else if status := x.Body.Status.NewConnectionStatus; status != "Connected" {
+		t.Errorf("%s: status parsed as %q", name, status)
+	}
+}
+
+func TestDeclaredEncoding(t *testing.T) {
+	declarations := map[string]string{
+		`<?xml version="1.0" encoding="Windows-1252"`: "windows-1252",
+		`<?xml version='1.0' encoding = 'cp1252' `:    "cp1252",
+		`<?xml version="1.0"`:                         "",
+	}
+	for declaration, expected := range declarations {
+		if actual := declaredEncoding([]byte(declaration)); actual != expected {
+			t.Errorf("%s declares %q, expected %q", declaration, actual, expected)
+		}
+	}
+}
+
+func TestCharsetReader(t *testing.T) {
+	if _, err := charsetReader("ISO-8859-1", strings.NewReader("")); err != nil {
+		t.Error(err)
+	}
+	if _, err := charsetReader("Shift_JIS", strings.NewReader("")); err == nil {
+		t.Error("Expected unsupported charsets to be rejected")
+	}
+	if actual := string(latin1ToUTF8([]byte("D\xe9co"))); actual != "Déco" {
+		t.Errorf("Converted as %q", actual)
+	}
+	if actual := string(windows1252ToUTF8([]byte("D\xe9co \x80\x81\x9f"))); actual != "Déco €\u0081Ÿ" {
+		t.Errorf("Converted as %q", actual)
+	}
+}
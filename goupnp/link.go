@@ -0,0 +1,129 @@
+package goupnp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// The error returned by the link config methods when the IGD implements none
+// of the WANDSLLinkConfig, WANEthernetLinkConfig and WANCableLinkConfig
+// services, or not the one asked for.
+var ErrNoLinkConfig = errors.New("IGD does not provide a WAN link config service")
+
+// This type summarizes the state of the WAN link carrying the connection of
+// an IGD, whichever link config service describes it. Fields which do not
+// apply to the kind of link, or which the IGD fails to report, are left zero.
+type LinkInfo struct {
+	// One of DSL, Ethernet or Cable, after the link config service found
+	Kind string
+	// Up or Down, or for cable links one of the CableLinkConfigState values
+	// such as operational
+	Status string
+
+	// DSL only, e.g. PPPoA and ADSL_G.lite
+	LinkType       string
+	ModulationType string
+
+	// Cable only, frequencies in Hz
+	DownstreamFrequency  uint32
+	DownstreamModulation string
+	UpstreamFrequency    uint32
+	UpstreamModulation   string
+}
+
+func (self *LinkInfo) String() string {
+	switch self.Kind {
+	case "DSL":
+		return fmt.Sprintf("DSL %s %s %s", self.Status, self.LinkType,
+			self.ModulationType)
+	case "Cable":
+		return fmt.Sprintf("Cable %s down %d Hz %s up %d Hz %s", self.Status,
+			self.DownstreamFrequency, self.DownstreamModulation,
+			self.UpstreamFrequency, self.UpstreamModulation)
+	}
+	return self.Kind + " " + self.Status
+}
+
+// This method returns a client for the WANDSLLinkConfig service of the
+// WANConnectionDevice carrying the connection the IGD is bound to, not that of
+// any other WANConnectionDevice.
+func (self *IGD) DSLLinkConfig() (*WANDSLLinkConfig1, error) {
+	service := self.serviceOfConnectionDevice(WANDSLLinkConfig1ServiceType)
+	if service == nil {
+		return nil, ErrNoLinkConfig
+	}
+	return &WANDSLLinkConfig1{igd: self, Service: service}, nil
+}
+
+// This method returns a client for the WANEthernetLinkConfig service of the
+// WANConnectionDevice carrying the connection the IGD is bound to, not that of
+// any other WANConnectionDevice.
+func (self *IGD) EthernetLinkConfig() (*WANEthernetLinkConfig1, error) {
+	service := self.serviceOfConnectionDevice(WANEthernetLinkConfig1ServiceType)
+	if service == nil {
+		return nil, ErrNoLinkConfig
+	}
+	return &WANEthernetLinkConfig1{igd: self, Service: service}, nil
+}
+
+// This method returns a client for the WANCableLinkConfig service of the
+// WANConnectionDevice carrying the connection the IGD is bound to, not that of
+// any other WANConnectionDevice.
+func (self *IGD) CableLinkConfig() (*WANCableLinkConfig1, error) {
+	service := self.serviceOfConnectionDevice(WANCableLinkConfig1ServiceType)
+	if service == nil {
+		return nil, ErrNoLinkConfig
+	}
+	return &WANCableLinkConfig1{igd: self, Service: service}, nil
+}
+
+// This method fetches a summary of the WAN link carrying the connection the
+// IGD is bound to from the first of the DSL, Ethernet and cable link config
+// services it implements. Only the status is required, the other details are
+// left zero if the IGD fails to report them.
+func (self *IGD) GetLinkInfo(ctx context.Context) (*LinkInfo, error) {
+	if client, err := self.DSLLinkConfig(); err == nil {
+		resp, err := client.GetDSLLinkInfo(ctx)
+		if err != nil {
+			return nil, err
+		}
+		info := &LinkInfo{Kind: "DSL", Status: resp.NewLinkStatus,
+			LinkType: resp.NewLinkType}
+		if resp, err := client.GetModulationType(ctx); err == nil {
+			info.ModulationType = resp.NewModulationType
+		}
+		return info, nil
+	}
+
+	if client, err := self.EthernetLinkConfig(); err == nil {
+		resp, err := client.GetEthernetLinkStatus(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return &LinkInfo{Kind: "Ethernet", Status: resp.NewEthernetLinkStatus}, nil
+	}
+
+	if client, err := self.CableLinkConfig(); err == nil {
+		resp, err := client.GetCableLinkConfigInfo(ctx)
+		if err != nil {
+			return nil, err
+		}
+		info := &LinkInfo{Kind: "Cable", Status: resp.NewCableLinkConfigState}
+		if resp, err := client.GetDownstreamFrequency(ctx); err == nil {
+			info.DownstreamFrequency = resp.NewDownstreamFrequency
+		}
+		if resp, err := client.GetDownstreamModulation(ctx); err == nil {
+			info.DownstreamModulation = resp.NewDownstreamModulation
+		}
+		if resp, err := client.GetUpstreamFrequency(ctx); err == nil {
+			info.UpstreamFrequency = resp.NewUpstreamFrequency
+		}
+		if resp, err := client.GetUpstreamModulation(ctx); err == nil {
+			info.UpstreamModulation = resp.NewUpstreamModulation
+		}
+		return info, nil
+	}
+
+	return nil, ErrNoLinkConfig
+}
@@ -0,0 +1,113 @@
+package goupnp
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// This function returns the Belkin description with a service of the passed
+// type alongside its connection service.
+func belkinDescriptionWithLinkConfig(serviceType string) string {
+	const connection = "<SCPDURL>/upnp/service/WANIPCn.xml</SCPDURL>\n\t\t\t\t\t\t\t</service>"
+	return strings.Replace(belkinDescription, connection, connection+`
+							<service>
+								<serviceType>`+serviceType+`</serviceType>
+								<serviceId>urn:upnp-org:serviceId:WANLinkC1</serviceId>
+								<controlURL>/upnp/control/WANLinkC1</controlURL>
+							</service>`, 1)
+}
+
+func TestGetLinkInfo(t *testing.T) {
+	handler := newSOAPTestHandler(t, func(action string, args map[string]string) map[string]string {
+		switch action {
+		case "GetDSLLinkInfo":
+			return map[string]string{"NewLinkType": "PPPoA", "NewLinkStatus": "Up"}
+		case "GetModulationType":
+			return map[string]string{"NewModulationType": "ADSL_G.lite"}
+		case "GetCableLinkConfigInfo":
+			return map[string]string{
+				"NewCableLinkConfigState": "operational",
+				"NewLinkType":             "Ethernet",
+			}
+		case "GetDownstreamFrequency":
+			return map[string]string{"NewDownstreamFrequency": "602000000"}
+		case "GetDownstreamModulation":
+			return map[string]string{"NewDownstreamModulation": "256QAM"}
+		}
+		return nil
+	})
+	newLinkIGD := func(serviceType string) *IGD {
+		return newTestIGDWith(t, loopbackInterface,
+			belkinDescriptionWithLinkConfig(serviceType), nil, handler)
+	}
+	ctx := context.Background()
+
+	info, err := newLinkIGD(WANDSLLinkConfig1ServiceType).GetLinkInfo(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Kind != "DSL" || info.Status != "Up" || info.LinkType != "PPPoA" ||
+		info.ModulationType != "ADSL_G.lite" {
+		t.Errorf("DSL link info incorrectly parsed as %+v", info)
+	}
+
+	info, err = newLinkIGD(WANCableLinkConfig1ServiceType).GetLinkInfo(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Kind != "Cable" || info.Status != "operational" ||
+		info.DownstreamFrequency != 602000000 || info.DownstreamModulation != "256QAM" ||
+		info.UpstreamModulation != "" {
+		t.Errorf("Cable link info incorrectly parsed as %+v", info)
+	}
+
+	if _, err := newLinkIGD(WANEthernetLinkConfig1ServiceType).GetLinkInfo(ctx); err == nil {
+		t.Error("Expected the failure of GetEthernetLinkStatus to be reported")
+	}
+
+	igd := newLinkIGD("urn:example-com:service:Other:1")
+	if _, err := igd.GetLinkInfo(ctx); err != ErrNoLinkConfig {
+		t.Errorf("Expected ErrNoLinkConfig, got %v", err)
+	}
+}
+
+func TestLinkConfigOfSiblingConnectionDevice(t *testing.T) {
+	connectionDevice := func(udn string, services ...Service) Device {
+		return Device{
+			DeviceType: "urn:schemas-upnp-org:device:WANConnectionDevice:1",
+			UDN:        udn,
+			Services: append([]Service{{
+				ServiceType: connectionTypeStringWANIP,
+				ServiceID:   "urn:upnp-org:serviceId:WANIPConn1",
+			}}, services...),
+		}
+	}
+	// Only the second WANConnectionDevice describes its link
+	igd := &IGD{description: &DeviceDescription{Device: Device{
+		Devices: []Device{{
+			DeviceType: "urn:schemas-upnp-org:device:WANDevice:1",
+			Devices: []Device{
+				connectionDevice("uuid:conn1"),
+				connectionDevice("uuid:conn2", Service{
+					ServiceType: WANDSLLinkConfig1ServiceType,
+					ServiceID:   "urn:upnp-org:serviceId:WANDSLLinkC1",
+				}),
+			},
+		}},
+	}}}
+	services := igd.ConnectionServices()
+
+	igd.connection = services[0]
+	if client, err := igd.DSLLinkConfig(); err != ErrNoLinkConfig {
+		t.Errorf("Expected ErrNoLinkConfig, got %v, %v", client, err)
+	}
+	igd.connection = services[1]
+	client, err := igd.DSLLinkConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if client.Service.ServiceID != "urn:upnp-org:serviceId:WANDSLLinkC1" {
+		t.Errorf("DSLLinkConfig bound to %v", client.Service)
+	}
+}
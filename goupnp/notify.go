@@ -0,0 +1,58 @@
+package goupnp
+
+import (
+	"errors"
+
+	"log/slog"
+
+	"github.com/nhelke/goupnpc/goupnp/ssdp"
+)
+
+// These are aliases of the types of package ssdp, kept here as they are
+// returned by ListenSSDP().
+type (
+	Announcement = ssdp.Announcement
+	Listener     = ssdp.Listener
+)
+
+// This function joins the SSDP multicast groups on each interface allowed by
+// the passed options, 239.255.255.250 for those with an IPv4 address and
+// ff02::c and ff05::c for those with an IPv6 one, and returns a Listener
+// streaming the NOTIFY announcements received. A nil opts is equivalent to the
+// zero DiscoveryOptions.
+//
+// An error is only returned if no group could be joined at all.
+func ListenSSDP(opts *DiscoveryOptions) (*Listener, error) {
+	listener := ssdp.NewListener()
+
+	// Several addresses of the same family may be selected on one interface,
+	// yet each group must only be joined once per interface
+	joined := make(map[string]bool)
+	var lastErr error
+	for _, local := range localInterfaces(opts) {
+		network := "udp4"
+		if local.isIPv6() {
+			network = "udp6"
+		}
+		key := local.iface.Name + " " + network
+		if joined[key] {
+			continue
+		}
+		if err := listener.Join(network, local.iface); err != nil {
+			slog.Warn("Failed to join SSDP groups", "iface", local.iface.Name,
+				"network", network, "error", err)
+			lastErr = err
+			continue
+		}
+		joined[key] = true
+	}
+
+	if len(joined) == 0 {
+		if lastErr == nil {
+			lastErr = errors.New("No usable interface to listen on")
+		}
+		listener.Close()
+		return nil, lastErr
+	}
+	return listener, nil
+}
@@ -0,0 +1,131 @@
+package goupnp
+
+import (
+	"net"
+	"strings"
+
+	"log/slog"
+)
+
+// This type allows callers to restrict or widen the set of local interfaces
+// discovery is performed on. The zero value, as well as a nil pointer, yields
+// the default behaviour of searching on every IPv4 address in the private
+// network range and on every IPv6 link-local address.
+//
+// Entries of both Include and Exclude may be an interface name such as
+// "eth0", a CIDR such as "100.64.0.0/10" or a single IP address. This allows
+// discovering IGDs on CGNAT-range, link-local or publicly-addressed LANs which
+// the default behaviour ignores.
+type DiscoveryOptions struct {
+	// When non-empty only local addresses matching one of these entries are
+	// used, whether or not they are in the private network range or link-local
+	Include []string
+	// Local addresses matching any of these entries are never used, even if
+	// they also match an entry of Include
+	Exclude []string
+	// When set, device descriptions, SCPDs and SOAP responses not conforming
+	// to the specifications, e.g. lacking the required namespaces or not
+	// encoded in UTF-8, are rejected rather than parsed leniently
+	Strict bool
+}
+
+func (self *DiscoveryOptions) strict() bool {
+	return self != nil && self.Strict
+}
+
+// This type pairs a local address discovery is performed from with the
+// interface holding it, so that sockets may be bound to the device itself
+// rather than only the source address.
+type localInterface struct {
+	iface *net.Interface
+	addr  *net.UDPAddr
+}
+
+func (self localInterface) isIPv6() bool {
+	return self.addr.IP.To4() == nil
+}
+
+// This function returns true if and only if the passed local address on the
+// interface named ifaceName matches the passed Include/Exclude entry.
+func matchesEntry(entry, ifaceName string, ip net.IP) bool {
+	entry = strings.TrimSpace(entry)
+	if _, ipNet, err := net.ParseCIDR(entry); err == nil {
+		return ipNet.Contains(ip)
+	}
+	if entryIP := net.ParseIP(entry); entryIP != nil {
+		return entryIP.Equal(ip)
+	}
+	return entry == ifaceName
+}
+
+// This method returns true if and only if discovery should be performed from
+// the passed local address on the interface named ifaceName.
+func (self *DiscoveryOptions) allows(ifaceName string, ip net.IP) bool {
+	if self != nil {
+		for _, entry := range self.Exclude {
+			if matchesEntry(entry, ifaceName, ip) {
+				return false
+			}
+		}
+	}
+	if self == nil || len(self.Include) == 0 {
+		if ip.To4() == nil {
+			return ip.IsLinkLocalUnicast()
+		}
+		return IsPrivateIPAddress(ip)
+	}
+	for _, entry := range self.Include {
+		if matchesEntry(entry, ifaceName, ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns all local interface IP addresses allowed by the passed options, by
+// default those in the private network range. They are traversed in the order
+// returned by `net.Interfaces()` and `net.Interface.Addrs()`.
+func localInterfaces(opts *DiscoveryOptions) (ret []localInterface) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		slog.Warn("Error", "err", err)
+		return
+	}
+	for i := range ifaces {
+		if ifaces[i].Flags&net.FlagUp == 0 {
+			continue
+		}
+		addrs, err := ifaces[i].Addrs()
+		if err != nil {
+			slog.Warn("Error", "err", err)
+			continue
+		}
+		for j := range addrs {
+			var ip net.IP
+
+			if addr, ok := addrs[j].(*net.IPNet); ok {
+				ip = addr.IP
+			} else if addr, ok := addrs[j].(*net.IPAddr); ok {
+				ip = addr.IP
+			}
+
+			if ip == nil || ip.IsLoopback() {
+				continue
+			}
+			if opts.allows(ifaces[i].Name, ip) {
+				slog.Debug("Found usable addr", "iface", ifaces[i].Name, "ip", ip)
+				addr := &net.UDPAddr{IP: ip, Port: 0}
+				// Link-local addresses are meaningless without the zone
+				// identifying the link they belong to
+				if ip.To4() == nil && ip.IsLinkLocalUnicast() {
+					addr.Zone = ifaces[i].Name
+				}
+				ret = append(ret, localInterface{
+					iface: &ifaces[i],
+					addr:  addr,
+				})
+			}
+		}
+	}
+	return
+}
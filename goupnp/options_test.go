@@ -0,0 +1,33 @@
+package goupnp
+
+import (
+	"net"
+	"testing"
+)
+
+func TestDiscoveryOptions(t *testing.T) {
+	tests := []struct {
+		opts    *DiscoveryOptions
+		iface   string
+		ip      net.IP
+		allowed bool
+	}{
+		{nil, "eth0", net.IPv4(192, 168, 1, 10), true},
+		{nil, "eth0", net.IPv4(100, 64, 3, 7), false},
+		{&DiscoveryOptions{}, "eth0", net.IPv4(10, 0, 0, 2), true},
+		{&DiscoveryOptions{Include: []string{"100.64.0.0/10"}}, "eth0", net.IPv4(100, 64, 3, 7), true},
+		{&DiscoveryOptions{Include: []string{"100.64.0.0/10"}}, "eth0", net.IPv4(192, 168, 1, 10), false},
+		{&DiscoveryOptions{Include: []string{"wlan0"}}, "wlan0", net.IPv4(169, 254, 12, 1), true},
+		{&DiscoveryOptions{Include: []string{"wlan0"}}, "eth0", net.IPv4(192, 168, 1, 10), false},
+		{&DiscoveryOptions{Include: []string{"203.0.113.5"}}, "eth0", net.IPv4(203, 0, 113, 5), true},
+		{&DiscoveryOptions{Exclude: []string{"docker0"}}, "docker0", net.IPv4(172, 17, 0, 1), false},
+		{&DiscoveryOptions{Include: []string{"10.0.0.0/8"}, Exclude: []string{"10.8.0.0/16"}}, "tun0", net.IPv4(10, 8, 0, 2), false},
+	}
+
+	for _, test := range tests {
+		if allowed := test.opts.allows(test.iface, test.ip); allowed != test.allowed {
+			t.Errorf("%+v allows %v on %v: %v, expected %v", test.opts,
+				test.ip, test.iface, allowed, test.allowed)
+		}
+	}
+}
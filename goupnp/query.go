@@ -0,0 +1,109 @@
+package goupnp
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// The namespace of the QueryStateVariable action, which every service
+// implements on behalf of the control protocol rather than of its own type.
+const controlNamespace = "urn:schemas-upnp-org:control-1-0"
+
+type queryStateVariableRequest struct {
+	VarName string `xml:"u:varName"`
+}
+
+// This method queries the value of the passed state variable of the passed
+// service of the IGD, which must be one of those found in its Description(),
+// by way of the QueryStateVariable action of the control protocol. Older IGDs
+// expose some values, such as PortMappingNumberOfEntries, this way only.
+//
+// The variable must be listed in the SCPD of the service, whose dataType
+// determines the type of the returned value: an unsigned or signed integer of
+// the matching size for ui1 to ui8 and i1 to i8, float32 or float64 for the
+// floating point types, bool for boolean, time.Time for the date and time
+// types, []byte for bin.base64 and bin.hex and string otherwise. Failures
+// reported by the IGD are returned as *UPnPError.
+//
+// The action is deprecated by the UPnP Device Architecture 1.1, hence many
+// IGDs do not implement it.
+func (self *IGD) QueryStateVariable(ctx context.Context, service *Service,
+	name string) (any, error) {
+	scpd, err := self.scpd(ctx, service)
+	if err != nil {
+		return nil, fmt.Errorf("QueryStateVariable: %w", err)
+	}
+	variable := scpd.StateVariable(name)
+	if variable == nil {
+		return nil, fmt.Errorf("QueryStateVariable: %s has no state variable %q",
+			service.ServiceType, name)
+	}
+	controlURL, err := self.resolveURL(service.ControlURL)
+	if err != nil {
+		return nil, fmt.Errorf("QueryStateVariable: %w", err)
+	}
+
+	envelope, err := marshalSOAP(controlNamespace, "QueryStateVariable",
+		queryStateVariableRequest{name})
+	if err != nil {
+		return nil, err
+	}
+	body, err := postSOAP(ctx, controlURL, controlNamespace, "QueryStateVariable",
+		bytes.NewReader(envelope))
+	if err != nil {
+		return nil, fmt.Errorf("QueryStateVariable: %w", err)
+	}
+	out, err := parseSOAPResponse(body, self.strict)
+	if err != nil {
+		return nil, fmt.Errorf("QueryStateVariable: %w", err)
+	}
+	value, ok := out["return"]
+	if !ok {
+		return nil, fmt.Errorf("QueryStateVariable: %s: no return value", name)
+	}
+	ret, err := parseStateValue(variable.DataType, value)
+	if err != nil {
+		return nil, fmt.Errorf("QueryStateVariable: %s: %w", name, err)
+	}
+	return ret, nil
+}
+
+// This function converts the passed value of a state variable of the passed
+// UPnP data type, see QueryStateVariable() for the resulting types.
+func parseStateValue(dataType, value string) (any, error) {
+	switch strings.TrimSpace(dataType) {
+	case "ui1":
+		return parseUint[uint8](value, 8)
+	case "ui2":
+		return parseUint[uint16](value, 16)
+	case "ui4":
+		return parseUint[uint32](value, 32)
+	case "ui8":
+		return parseUint[uint64](value, 64)
+	case "i1":
+		return parseInt[int8](value, 8)
+	case "i2":
+		return parseInt[int16](value, 16)
+	case "i4", "int":
+		return parseInt[int32](value, 32)
+	case "i8":
+		return parseInt[int64](value, 64)
+	case "r4":
+		return parseFloat[float32](value, 32)
+	case "r8", "number", "float", "fixed.14.4":
+		return parseFloat[float64](value, 64)
+	case "boolean":
+		return parseBool(value)
+	case "date", "dateTime", "dateTime.tz", "time", "time.tz":
+		return parseDateTime(value)
+	case "bin.base64":
+		return base64.StdEncoding.DecodeString(strings.TrimSpace(value))
+	case "bin.hex":
+		return hex.DecodeString(strings.TrimSpace(value))
+	}
+	return value, nil
+}
@@ -0,0 +1,82 @@
+package goupnp
+
+import (
+	"context"
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestQueryStateVariable(t *testing.T) {
+	scpd, err := os.ReadFile("scpd/WANIPConnection1.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	igd := newTestIGDWith(t, loopbackInterface, belkinDescription,
+		map[string]string{"/upnp/service/WANIPCn.xml": string(scpd)},
+		newSOAPTestHandler(t, func(action string, args map[string]string) map[string]string {
+			if action != "QueryStateVariable" {
+				return nil
+			}
+			switch args["varName"] {
+			case "PortMappingNumberOfEntries":
+				return map[string]string{"return": "12"}
+			case "ExternalIPAddress":
+				return map[string]string{"return": "203.0.113.7"}
+			case "PortMappingEnabled":
+				return map[string]string{"return": "maybe"}
+			}
+			return nil
+		}))
+	service := igd.ConnectionService()
+	ctx := context.Background()
+
+	value, err := igd.QueryStateVariable(ctx, service, "PortMappingNumberOfEntries")
+	if err != nil || value != uint16(12) {
+		t.Errorf("PortMappingNumberOfEntries queried as %#v, %v", value, err)
+	}
+	value, err = igd.QueryStateVariable(ctx, service, "ExternalIPAddress")
+	if err != nil || value != "203.0.113.7" {
+		t.Errorf("ExternalIPAddress queried as %#v, %v", value, err)
+	}
+	if _, err := igd.QueryStateVariable(ctx, service, "PortMappingEnabled"); err == nil {
+		t.Error("Expected an error converting an invalid boolean")
+	}
+	var upnpErr *UPnPError
+	if _, err := igd.QueryStateVariable(ctx, service, "Uptime"); !errors.As(err, &upnpErr) {
+		t.Errorf("Expected a UPnPError, got %v", err)
+	}
+	if _, err := igd.QueryStateVariable(ctx, service, "Bogus"); err == nil {
+		t.Error("Expected an error querying a variable missing from the SCPD")
+	}
+}
+
+func TestParseStateValue(t *testing.T) {
+	values := []struct {
+		dataType, value string
+		expected        any
+	}{
+		{"ui1", "255", uint8(255)},
+		{"ui4", " 4294967295 ", uint32(4294967295)},
+		{"i2", "-2", int16(-2)},
+		{"int", "42", int32(42)},
+		{"r8", "1.5", float64(1.5)},
+		{"boolean", "yes", true},
+		{"dateTime", "2024-03-01T12:00:00", time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)},
+		{"bin.hex", "cafe", []byte{0xca, 0xfe}},
+		{"bin.base64", "yv4=", []byte{0xca, 0xfe}},
+		{"string", " as is ", " as is "},
+		{"uuid", "1234", "1234"},
+	}
+	for _, v := range values {
+		actual, err := parseStateValue(v.dataType, v.value)
+		if err != nil || !reflect.DeepEqual(actual, v.expected) {
+			t.Errorf("%s %q parsed as %#v, %v", v.dataType, v.value, actual, err)
+		}
+	}
+	if _, err := parseStateValue("ui1", "256"); err == nil {
+		t.Error("Expected an error parsing an out of range ui1")
+	}
+}
@@ -0,0 +1,84 @@
+package goupnp
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"sort"
+	"sync"
+	"time"
+)
+
+// How long ranking waits for the IGDs to report their connection status, an
+// IGD which fails to answer in time is ranked as not connected.
+const rankTimeout = 2 * time.Second
+
+// This type describes how a discovered IGD was ranked against the others
+// found at the same time. IGDs are ordered by the following criteria, each
+// only being considered when all previous ones are equal:
+//
+//  1. Whether the IGD is the gateway of one of our default routes
+//  2. Whether its WAN connection reports being Connected
+//  3. The metric of the default route of the interface the IGD was found on,
+//     lower being better
+//
+// This ensures that on multi-homed hosts (docker0, VPNs, lab networks…) the
+// router which actually carries our traffic is preferred.
+type Rank struct {
+	DefaultGateway bool
+	Connected      bool
+	// Metric is math.MaxInt when the interface has no default route
+	Metric int
+
+	host net.IP
+}
+
+func (self Rank) String() string {
+	return fmt.Sprint("gateway=", self.DefaultGateway, " connected=",
+		self.Connected, " metric=", self.Metric)
+}
+
+// This method returns true if and only if self ranks strictly better than
+// other.
+func (self Rank) Better(other Rank) bool {
+	if self.DefaultGateway != other.DefaultGateway {
+		return self.DefaultGateway
+	}
+	if self.Connected != other.Connected {
+		return self.Connected
+	}
+	return self.Metric < other.Metric
+}
+
+// This function fills in the Rank of each passed IGD, querying their
+// connection status concurrently within ctx, and sorts them from best to
+// worst.
+func rankIGDs(ctx context.Context, igds []*IGD) {
+	gateways := defaultGateways()
+	var wg sync.WaitGroup
+	for _, igd := range igds {
+		igd.rank.Metric = math.MaxInt
+		for _, gw := range gatewaysFor(igd.iface) {
+			if gw.Metric < igd.rank.Metric {
+				igd.rank.Metric = gw.Metric
+			}
+		}
+		for _, gw := range gateways {
+			if gw.IP.Equal(igd.rank.host) {
+				igd.rank.DefaultGateway = true
+			}
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if status, ok := igd.connectionStatus(ctx); ok {
+				igd.rank.Connected = status.Connected
+			}
+		}()
+	}
+	wg.Wait()
+	sort.SliceStable(igds, func(i, j int) bool {
+		return igds[i].rank.Better(igds[j].rank)
+	})
+}
@@ -0,0 +1,70 @@
+package goupnp
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestRankOrdering(t *testing.T) {
+	ranks := []Rank{
+		{Metric: math.MaxInt},
+		{Connected: true, Metric: 100},
+		{DefaultGateway: true, Metric: 600},
+		{Connected: true, Metric: 50},
+		{DefaultGateway: true, Connected: true, Metric: 600},
+	}
+	sort.SliceStable(ranks, func(i, j int) bool {
+		return ranks[i].Better(ranks[j])
+	})
+
+	expected := []Rank{
+		{DefaultGateway: true, Connected: true, Metric: 600},
+		{DefaultGateway: true, Metric: 600},
+		{Connected: true, Metric: 50},
+		{Connected: true, Metric: 100},
+		{Metric: math.MaxInt},
+	}
+	for i := range expected {
+		if ranks[i].String() != expected[i].String() {
+			t.Errorf("Rank %d is %v, expected %v", i, ranks[i], expected[i])
+		}
+	}
+}
+
+func TestRankIGDsTimeout(t *testing.T) {
+	// An IGD accepting connections but never answering SOAP requests
+	stop := make(chan struct{})
+	defer close(stop)
+	hanging := newTestIGDWith(t, loopbackInterface, belkinDescription, nil,
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-stop
+		}))
+	connected := newSOAPTestIGD(t, func(action string, args map[string]string) map[string]string {
+		switch action {
+		case "GetStatusInfo":
+			return map[string]string{"NewConnectionStatus": "Connected"}
+		case "GetExternalIPAddress":
+			return map[string]string{"NewExternalIPAddress": "203.0.113.7"}
+		}
+		return nil
+	})
+	igds := []*IGD{hanging, connected}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	rankIGDs(ctx, igds)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Ranking took %v despite the deadline", elapsed)
+	}
+	if igds[0] != connected || !igds[0].Rank().Connected {
+		t.Errorf("Connected IGD ranked as %v", igds[0].Rank())
+	}
+	if igds[1].Rank().Connected {
+		t.Errorf("Hanging IGD ranked as %v", igds[1].Rank())
+	}
+}
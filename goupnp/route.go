@@ -0,0 +1,148 @@
+package goupnp
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/hex"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// This type describes a default route as found in the kernel routing table,
+// that is the address of the router our traffic leaves through and the
+// interface it is reached on.
+type gateway struct {
+	Iface  string
+	IP     net.IP
+	Metric int
+}
+
+// Flag set by the kernel on routes which go through a gateway, see route(8)
+const rtfGateway = 0x2
+
+// This function parses the contents of /proc/net/route and returns the
+// default IPv4 gateways it contains. Lines which cannot be parsed are
+// silently skipped.
+//
+// Addresses in this file are written as hexadecimal numbers in host byte
+// order, hence the use of binary.NativeEndian below.
+func parseIPv4Routes(r io.Reader) (ret []gateway) {
+	scanner := bufio.NewScanner(r)
+	// The first line is a header naming the columns
+	scanner.Scan()
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 8 {
+			continue
+		}
+		// Iface Destination Gateway Flags RefCnt Use Metric Mask ...
+		if fields[1] != "00000000" || fields[7] != "00000000" {
+			continue
+		}
+		flags, err := strconv.ParseUint(fields[3], 16, 32)
+		if err != nil || flags&rtfGateway == 0 {
+			continue
+		}
+		gw, err := strconv.ParseUint(fields[2], 16, 32)
+		if err != nil || gw == 0 {
+			continue
+		}
+		metric, _ := strconv.Atoi(fields[6])
+		ip := make(net.IP, net.IPv4len)
+		binary.NativeEndian.PutUint32(ip, uint32(gw))
+		ret = append(ret, gateway{Iface: fields[0], IP: ip, Metric: metric})
+	}
+	return
+}
+
+// This function parses the contents of /proc/net/ipv6_route and returns the
+// default IPv6 gateways it contains. Unlike its IPv4 counterpart, addresses in
+// this file are written in network byte order.
+func parseIPv6Routes(r io.Reader) (ret []gateway) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 10 {
+			continue
+		}
+		// dst dstLen src srcLen nextHop metric refCnt use flags iface
+		if strings.Trim(fields[0], "0") != "" || fields[1] != "00" {
+			continue
+		}
+		flags, err := strconv.ParseUint(fields[8], 16, 32)
+		if err != nil || flags&rtfGateway == 0 {
+			continue
+		}
+		nextHop, err := hex.DecodeString(fields[4])
+		if err != nil || len(nextHop) != net.IPv6len {
+			continue
+		}
+		ip := net.IP(nextHop)
+		if ip.IsUnspecified() {
+			continue
+		}
+		metric, _ := strconv.ParseUint(fields[5], 16, 32)
+		ret = append(ret, gateway{Iface: fields[9], IP: ip, Metric: int(metric)})
+	}
+	return
+}
+
+// This function returns the default gateways reached through the interface
+// which holds the passed local address.
+func gatewaysFor(local net.IP) (ret []gateway) {
+	gateways := defaultGateways()
+	if len(gateways) == 0 {
+		return
+	}
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return
+	}
+	for i := range ifaces {
+		if !interfaceHasIP(&ifaces[i], local) {
+			continue
+		}
+		for _, gw := range gateways {
+			if gw.Iface == ifaces[i].Name {
+				ret = append(ret, gw)
+			}
+		}
+	}
+	return
+}
+
+// This function returns true if and only if the passed IP address is assigned
+// to the passed interface.
+func interfaceHasIP(iface *net.Interface, ip net.IP) bool {
+	addrs, err := iface.Addrs()
+	if err != nil {
+		return false
+	}
+	for _, addr := range addrs {
+		if ipNet, ok := addr.(*net.IPNet); ok && ipNet.IP.Equal(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// This function returns the first IPv4 address among the passed interface
+// addresses, preferably one in the private network range, or nil if there is
+// none besides loopback ones.
+func ipv4Addr(addrs []net.Addr) (ret net.IP) {
+	for _, addr := range addrs {
+		ipNet, ok := addr.(*net.IPNet)
+		if !ok || ipNet.IP.To4() == nil || ipNet.IP.IsLoopback() {
+			continue
+		}
+		if IsPrivateIPAddress(ipNet.IP) {
+			return ipNet.IP.To4()
+		}
+		if ret == nil {
+			ret = ipNet.IP.To4()
+		}
+	}
+	return
+}
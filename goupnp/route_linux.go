@@ -0,0 +1,25 @@
+package goupnp
+
+import (
+	"os"
+
+	"log/slog"
+)
+
+// This function reads the kernel routing tables and returns every default
+// gateway found therein, IPv4 first.
+func defaultGateways() (ret []gateway) {
+	if f, err := os.Open("/proc/net/route"); err == nil {
+		ret = append(ret, parseIPv4Routes(f)...)
+		f.Close()
+	} else {
+		slog.Debug("Could not read IPv4 routing table", "error", err)
+	}
+	if f, err := os.Open("/proc/net/ipv6_route"); err == nil {
+		ret = append(ret, parseIPv6Routes(f)...)
+		f.Close()
+	} else {
+		slog.Debug("Could not read IPv6 routing table", "error", err)
+	}
+	return
+}
@@ -0,0 +1,9 @@
+//go:build !linux
+
+package goupnp
+
+// Reading the routing table is only supported on Linux for the time being, so
+// the gateway based fallbacks are simply not attempted elsewhere.
+func defaultGateways() []gateway {
+	return nil
+}
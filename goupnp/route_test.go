@@ -0,0 +1,64 @@
+package goupnp
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+)
+
+func TestIPv4RouteParsing(t *testing.T) {
+	gw := binary.NativeEndian.Uint32(net.IPv4(192, 168, 2, 1).To4())
+	subnet := binary.NativeEndian.Uint32(net.IPv4(192, 168, 2, 0).To4())
+	mask := binary.NativeEndian.Uint32(net.IPv4(255, 255, 255, 0).To4())
+	table := "Iface\tDestination\tGateway \tFlags\tRefCnt\tUse\tMetric\tMask\t\tMTU\tWindow\tIRTT\n" +
+		fmt.Sprintf("wlan0\t00000000\t%08X\t0003\t0\t0\t600\t00000000\t0\t0\t0\n", gw) +
+		fmt.Sprintf("wlan0\t%08X\t00000000\t0001\t0\t0\t600\t%08X\t0\t0\t0\n", subnet, mask) +
+		"tun0\t00000000\t00000000\t0001\t0\t0\t50\t00000000\t0\t0\t0\n"
+
+	gateways := parseIPv4Routes(strings.NewReader(table))
+	if len(gateways) != 1 {
+		t.Fatalf("Expected exactly one gateway, got %v", gateways)
+	}
+	if g := gateways[0]; g.Iface != "wlan0" || !g.IP.Equal(net.IPv4(192, 168, 2, 1)) || g.Metric != 600 {
+		t.Errorf("Gateway incorrectly parsed as %+v", g)
+	}
+}
+
+func TestIPv6RouteParsing(t *testing.T) {
+	const table = `fd000000000000000000000000000000 40 00000000000000000000000000000000 00 00000000000000000000000000000000 00000100 00000001 00000000 00000001     eth0
+00000000000000000000000000000000 00 00000000000000000000000000000000 00 fe80000000000000021122fffe334455 00000400 00000001 00000000 00000003     eth0
+00000000000000000000000000000000 00 00000000000000000000000000000000 00 00000000000000000000000000000000 ffffffff 00000001 00000000 00200200       lo
+`
+	gateways := parseIPv6Routes(strings.NewReader(table))
+	if len(gateways) != 1 {
+		t.Fatalf("Expected exactly one gateway, got %v", gateways)
+	}
+	if g := gateways[0]; g.Iface != "eth0" || !g.IP.Equal(net.ParseIP("fe80::211:22ff:fe33:4455")) || g.Metric != 1024 {
+		t.Errorf("Gateway incorrectly parsed as %+v", g)
+	}
+}
+
+func TestIPv4Addr(t *testing.T) {
+	ipNet := func(cidr string) net.Addr {
+		ip, ipNet, _ := net.ParseCIDR(cidr)
+		ipNet.IP = ip
+		return ipNet
+	}
+	addrs := []net.Addr{
+		ipNet("fe80::1/64"),
+		ipNet("127.0.0.1/8"),
+		ipNet("203.0.113.5/24"),
+		ipNet("192.168.1.10/24"),
+	}
+	if ip := ipv4Addr(addrs); !ip.Equal(net.IPv4(192, 168, 1, 10)) {
+		t.Errorf("Expected the private address, got %v", ip)
+	}
+	if ip := ipv4Addr(addrs[:3]); !ip.Equal(net.IPv4(203, 0, 113, 5)) {
+		t.Errorf("Expected the public address, got %v", ip)
+	}
+	if ip := ipv4Addr(addrs[:2]); ip != nil {
+		t.Errorf("Expected no address, got %v", ip)
+	}
+}
@@ -0,0 +1,264 @@
+package goupnp
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"log/slog"
+)
+
+// This type models the service description document (SCPD) found at the
+// SCPDURL of each service, which lists the actions the service implements and
+// the state variables their arguments relate to.
+type SCPD struct {
+	XMLName xml.Name `xml:"scpd"`
+
+	SpecVersion SpecVersion `xml:"specVersion"`
+
+	Actions        []Action        `xml:"actionList>action"`
+	StateVariables []StateVariable `xml:"serviceStateTable>stateVariable"`
+}
+
+type Action struct {
+	Name      string     `xml:"name"`
+	Arguments []Argument `xml:"argumentList>argument"`
+}
+
+// This type describes an argument of an Action. Direction is either "in" or
+// "out" and the type of the argument is that of its related state variable.
+type Argument struct {
+	Name                 string `xml:"name"`
+	Direction            string `xml:"direction"`
+	RelatedStateVariable string `xml:"relatedStateVariable"`
+}
+
+// This type describes a state variable of a service. DataType is one of the
+// UPnP data types such as "ui2", "ui4", "boolean" or "string".
+//
+// AllowedValues is only set for string variables restricted to a list of
+// values and AllowedValueRange only for numeric variables restricted to a
+// range.
+type StateVariable struct {
+	Name              string             `xml:"name"`
+	DataType          string             `xml:"dataType"`
+	SendEvents        string             `xml:"sendEvents,attr"`
+	DefaultValue      string             `xml:"defaultValue"`
+	AllowedValues     []string           `xml:"allowedValueList>allowedValue"`
+	AllowedValueRange *AllowedValueRange `xml:"allowedValueRange"`
+}
+
+type AllowedValueRange struct {
+	Minimum string `xml:"minimum"`
+	Maximum string `xml:"maximum"`
+	Step    string `xml:"step"`
+}
+
+// This method returns true if and only if changes of the variable are sent to
+// subscribers by eventing. The attribute defaults to "yes" when absent.
+func (self *StateVariable) Evented() bool {
+	return !strings.EqualFold(self.SendEvents, "no")
+}
+
+const serviceNamespace = "urn:schemas-upnp-org:service-1-0"
+
+// This function parses the passed service description document, as leniently
+// as ParseDeviceDescription().
+func ParseSCPD(body []byte) (*SCPD, error) {
+	return parseSCPD(body, false)
+}
+
+func parseSCPD(body []byte, strict bool) (*SCPD, error) {
+	var x SCPD
+	if err := decodeXML(body, &x, strict); err != nil {
+		return nil, err
+	}
+	if err := checkNamespace(x.XMLName, serviceNamespace, strict); err != nil {
+		return nil, err
+	}
+	return &x, nil
+}
+
+// This method returns the action of the passed name, or nil if the service
+// does not implement it.
+func (self *SCPD) Action(name string) *Action {
+	for i := range self.Actions {
+		if self.Actions[i].Name == name {
+			return &self.Actions[i]
+		}
+	}
+	return nil
+}
+
+// This method returns the state variable of the passed name, or nil if the
+// service has no such variable.
+func (self *SCPD) StateVariable(name string) *StateVariable {
+	for i := range self.StateVariables {
+		if self.StateVariables[i].Name == name {
+			return &self.StateVariables[i]
+		}
+	}
+	return nil
+}
+
+// This method returns the arguments of the action whose direction is "in", in
+// the order they must be sent.
+func (self *Action) InArguments() []Argument {
+	return self.arguments("in")
+}
+
+// This method returns the arguments of the action whose direction is "out".
+func (self *Action) OutArguments() []Argument {
+	return self.arguments("out")
+}
+
+func (self *Action) arguments(direction string) (ret []Argument) {
+	for _, argument := range self.Arguments {
+		if strings.EqualFold(strings.TrimSpace(argument.Direction), direction) {
+			ret = append(ret, argument)
+		}
+	}
+	return
+}
+
+// This type answers which actions an IGD implements, as many of them are
+// optional and routers vary widely in which they provide. Use
+// IGD.Capabilities() to obtain one.
+type Capabilities struct {
+	connectionType string
+	scpds          map[string]*SCPD
+}
+
+// This method returns the SCPD of the service of the passed type, or nil if
+// the IGD does not provide such a service or its SCPD could not be fetched.
+func (self *Capabilities) SCPD(serviceType string) *SCPD {
+	return self.scpds[serviceType]
+}
+
+// This method returns true if and only if the connection service of the IGD,
+// WANIPConnection or WANPPPConnection, implements the passed action, e.g.
+// "DeletePortMappingRange".
+func (self *Capabilities) Supports(action string) bool {
+	return self.ServiceSupports(self.connectionType, action)
+}
+
+// This method returns true if and only if the service of the passed type
+// implements the passed action.
+func (self *Capabilities) ServiceSupports(serviceType, action string) bool {
+	scpd := self.SCPD(serviceType)
+	return scpd != nil && scpd.Action(action) != nil
+}
+
+// This method fetches the SCPD of every service of the IGD, caching them for
+// later calls, and returns the resulting Capabilities.
+//
+// The channel is sent a value even if some SCPDs could not be fetched, those
+// services are then reported as implementing no action.
+func (self *IGD) Capabilities() (ret chan *Capabilities) {
+	ret = make(chan *Capabilities, 1)
+
+	go func() {
+		connection := self.ConnectionService()
+		capabilities := Capabilities{
+			connectionType: connection.ServiceType,
+			scpds:          make(map[string]*SCPD),
+		}
+		// The connection service the IGD is bound to comes first so that
+		// its SCPD is the one kept should there be others of the same type
+		services := append([]*Service{connection},
+			self.description.Device.FindServices()...)
+		for _, service := range services {
+			if _, ok := capabilities.scpds[service.ServiceType]; ok {
+				continue
+			}
+			if scpd, err := self.scpd(context.Background(), service); err == nil {
+				capabilities.scpds[service.ServiceType] = scpd
+			} else {
+				slog.Warn("Failed to fetch SCPD", "service", service.ServiceType, "error", err)
+			}
+		}
+		ret <- &capabilities
+		close(ret)
+	}()
+
+	return
+}
+
+// How long fetching an SCPD may take, whatever the context, and how long a
+// failure to fetch one is remembered before it is fetched anew.
+const (
+	scpdTimeout    = 5 * time.Second
+	scpdRetryDelay = time.Minute
+)
+
+// This type records the outcome of fetching the SCPD of a service, either
+// the SCPD or the error and when it occurred.
+type scpdEntry struct {
+	scpd     *SCPD
+	err      error
+	failedAt time.Time
+}
+
+// This method returns the SCPD of the passed service of the IGD, fetching it
+// within ctx on first use. A failure is returned again without fetching until
+// scpdRetryDelay has elapsed, unless it was only due to ctx being done.
+func (self *IGD) scpd(ctx context.Context, service *Service) (*SCPD, error) {
+	self.mutex.Lock()
+	entry, ok := self.scpds[service]
+	self.mutex.Unlock()
+	if ok && (entry.err == nil || time.Since(entry.failedAt) < scpdRetryDelay) {
+		return entry.scpd, entry.err
+	}
+
+	scpd, err := self.fetchSCPD(ctx, service)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		entry = &scpdEntry{err: err, failedAt: time.Now()}
+	} else {
+		entry = &scpdEntry{scpd: scpd}
+	}
+
+	self.mutex.Lock()
+	if self.scpds == nil {
+		self.scpds = make(map[*Service]*scpdEntry)
+	}
+	self.scpds[service] = entry
+	self.mutex.Unlock()
+	return scpd, err
+}
+
+// This method fetches and parses the SCPD of the passed service of the IGD
+// within ctx and scpdTimeout.
+func (self *IGD) fetchSCPD(ctx context.Context, service *Service) (*SCPD, error) {
+	scpdURL, err := self.resolveURL(service.SCPDURL)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, scpdTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, "GET", scpdURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	// We got something back, lets not leak it
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Fetching %v: %v", scpdURL, resp.Status)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	slog.Debug("SCPD XML", "url", scpdURL, "content", string(body))
+	return parseSCPD(body, self.strict)
+}
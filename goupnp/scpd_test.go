@@ -0,0 +1,176 @@
+package goupnp
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"testing"
+)
+
+const exampleWANIPConnectionSCPD = `<?xml version="1.0"?>
+<scpd xmlns="urn:schemas-upnp-org:service-1-0">
+	<specVersion><major>1</major><minor>0</minor></specVersion>
+	<actionList>
+		<action>
+			<name>DeletePortMapping</name>
+			<argumentList>
+				<argument>
+					<name>NewRemoteHost</name>
+					<direction>in</direction>
+					<relatedStateVariable>RemoteHost</relatedStateVariable>
+				</argument>
+				<argument>
+					<name>NewExternalPort</name>
+					<direction>in</direction>
+					<relatedStateVariable>ExternalPort</relatedStateVariable>
+				</argument>
+				<argument>
+					<name>NewProtocol</name>
+					<direction>in</direction>
+					<relatedStateVariable>PortMappingProtocol</relatedStateVariable>
+				</argument>
+			</argumentList>
+		</action>
+		<action>
+			<name>GetExternalIPAddress</name>
+			<argumentList>
+				<argument>
+					<name>NewExternalIPAddress</name>
+					<direction>out</direction>
+					<relatedStateVariable>ExternalIPAddress</relatedStateVariable>
+				</argument>
+			</argumentList>
+		</action>
+	</actionList>
+	<serviceStateTable>
+		<stateVariable sendEvents="no">
+			<name>RemoteHost</name>
+			<dataType>string</dataType>
+		</stateVariable>
+		<stateVariable sendEvents="no">
+			<name>ExternalPort</name>
+			<dataType>ui2</dataType>
+			<allowedValueRange>
+				<minimum>0</minimum>
+				<maximum>65535</maximum>
+			</allowedValueRange>
+		</stateVariable>
+		<stateVariable sendEvents="no">
+			<name>PortMappingProtocol</name>
+			<dataType>string</dataType>
+			<allowedValueList>
+				<allowedValue>TCP</allowedValue>
+				<allowedValue>UDP</allowedValue>
+			</allowedValueList>
+		</stateVariable>
+		<stateVariable sendEvents="yes">
+			<name>ExternalIPAddress</name>
+			<dataType>string</dataType>
+		</stateVariable>
+	</serviceStateTable>
+</scpd>
+`
+
+func TestSCPDParsing(t *testing.T) {
+	scpd, err := ParseSCPD([]byte(exampleWANIPConnectionSCPD))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	action := scpd.Action("DeletePortMapping")
+	if action == nil || len(action.InArguments()) != 3 || len(action.OutArguments()) != 0 ||
+		action.Arguments[1].RelatedStateVariable != "ExternalPort" {
+		t.Errorf("Action incorrectly parsed as %+v", action)
+	}
+	if scpd.Action("DeletePortMappingRange") != nil {
+		t.Error("Found an action the service does not implement")
+	}
+
+	protocol := scpd.StateVariable("PortMappingProtocol")
+	if protocol == nil || protocol.Evented() || len(protocol.AllowedValues) != 2 {
+		t.Errorf("State variable incorrectly parsed as %+v", protocol)
+	}
+	port := scpd.StateVariable("ExternalPort")
+	if port == nil || port.DataType != "ui2" || port.AllowedValueRange == nil ||
+		port.AllowedValueRange.Maximum != "65535" {
+		t.Errorf("State variable incorrectly parsed as %+v", port)
+	}
+}
+
+// The local interface test IGDs are discovered from unless stated otherwise.
+var loopbackInterface = localInterface{addr: &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)}}
+
+// This function starts a server serving the Belkin description, with its
+// URLBase pointing to the server itself, along with the passed additional
+// documents, and returns an IGD discovered from it over IPv4.
+func newTestIGD(t *testing.T, documents map[string]string) *IGD {
+	return newTestIGDWith(t, loopbackInterface, belkinDescription, documents, nil)
+}
+
+// This function behaves as newTestIGD() but discovers the IGD from the passed
+// local interface.
+func newTestIGDOn(t *testing.T, local localInterface, documents map[string]string) *IGD {
+	return newTestIGDWith(t, local, belkinDescription, documents, nil)
+}
+
+// This function starts a server playing every service of the Belkin IGD with
+// newSOAPTestHandler() and returns an IGD discovered from it over IPv4.
+func newSOAPTestIGD(t *testing.T,
+	handle func(action string, args map[string]string) map[string]string) *IGD {
+	return newTestIGDWith(t, loopbackInterface, belkinDescription, nil,
+		newSOAPTestHandler(t, handle))
+}
+
+// This function starts a server serving the passed description, with its
+// URLBase pointing to the server itself, along with the passed additional
+// documents, passing every other request to handler if not nil, and returns
+// an IGD discovered from it over the passed local interface.
+func newTestIGDWith(t *testing.T, local localInterface, description string,
+	documents map[string]string, handler http.Handler) *IGD {
+	var served string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/rootDesc.xml" {
+			w.Write([]byte(served))
+		} else if document, ok := documents[r.URL.Path]; ok && r.Method == "GET" {
+			w.Write([]byte(document))
+		} else if handler != nil {
+			handler.ServeHTTP(w, r)
+		} else {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	served = urlBasePattern.ReplaceAllLiteralString(description,
+		"<URLBase>"+server.URL+"</URLBase>")
+
+	descURL, _ := url.Parse(server.URL + "/rootDesc.xml")
+	igd, ok := newIGD(descURL, local, true)
+	if !ok {
+		t.Fatal("Failed to create IGD")
+	}
+	return igd
+}
+
+var urlBasePattern = regexp.MustCompile(`<URLBase>[^<]*</URLBase>`)
+
+func TestCapabilities(t *testing.T) {
+	igd := newTestIGD(t, map[string]string{
+		"/upnp/service/WANIPCn.xml": exampleWANIPConnectionSCPD,
+	})
+
+	capabilities, ok := <-igd.Capabilities()
+	if !ok {
+		t.Fatal("No capabilities returned")
+	}
+	if !capabilities.Supports("DeletePortMapping") ||
+		capabilities.Supports("DeletePortMappingRange") {
+		t.Error("Capabilities do not match the SCPD")
+	}
+	// The Belkin SCPDs of the other services are not served
+	if capabilities.ServiceSupports("urn:schemas-upnp-org:service:Layer3Forwarding:1",
+		"GetDefaultConnectionService") {
+		t.Error("Capabilities reported for a service whose SCPD is missing")
+	}
+}
@@ -1,58 +1,16 @@
 package goupnp
 
 import (
-	"bufio"
-	"bytes"
-	"encoding/xml"
-	"errors"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
 	"net/url"
 	"time"
 
 	"log/slog"
-)
-
-// Returns all local interface IP addresses in the private network range
-// They are traversed in the order returned by `net.InterfaceAddrs()`
-func localPrivateAddrs() (ret []*net.UDPAddr) {
-	addrs, err := net.InterfaceAddrs()
-	if err == nil {
-		for i := range addrs {
-			var ip net.IP
-
-			if addr, ok := addrs[i].(*net.IPNet); ok {
-				ip = addr.IP
-			} else if addr, ok := addrs[i].(*net.IPAddr); ok {
-				ip = addr.IP
-			}
 
-			if ip != nil {
-				if IsPrivateIPAddress(ip) {
-					slog.Debug("Found private addr", "ip", ip)
-					ret = append(ret, &net.UDPAddr{
-						IP:   ip,
-						Port: 0,
-					})
-				}
-			}
-		}
-	} else {
-		slog.Warn("Error", "err", err)
-	}
-	return
-}
-
-const (
-	ssdpIPv4Addr = "239.255.255.250"
-	ssdpPort     = 1900
-	format       = "M-SEARCH * HTTP/1.1\r\n" +
-		"HOST: %s:%d\r\n" +
-		"ST: %s\r\n" +
-		"MAN: \"ssdp:discover\"\r\n" +
-		"MX: %d\r\n" +
-		"\r\n"
+	"github.com/nhelke/goupnpc/goupnp/ssdp"
 )
 
 // These are the various device types we need to M-SEARCH the local subnet
@@ -69,117 +27,145 @@ var deviceTypes = []string{
 }
 
 // This function implements the strict minimum of SSDP in order to discover the
-// an IGD on the passed localBindAddr. The function blocks until a UPnP enabled
-// IGD is found or timeout of four seconds expires. Timeouts smaller than 3
-// seconds are unreasonable This function's behavior is not defined if the
-// passed localBindAddr is not an IP address in the private network range. You
-// may wish to use goupnp.localPrivateAddrs() to obtain a list of valid such
-// addresses for the localhost.
-func discoverIGDDescriptionURL(localBindAddr *net.UDPAddr) (u *url.URL, ok bool) {
-	multicastAddr, err := net.ResolveUDPAddr("udp4", fmt.Sprintf("%s:%d",
-		ssdpIPv4Addr, ssdpPort))
+// an IGD on the passed local interface. The function blocks until a UPnP
+// enabled IGD is found or timeout of four seconds expires. Timeouts smaller
+// than 3 seconds are unreasonable. You may wish to use
+// goupnp.localInterfaces() to obtain a list of valid such interfaces for the
+// localhost.
+//
+// IPv4 local addresses search the 239.255.255.250 multicast group whereas
+// IPv6 ones search both the link-local ff02::c and site-local ff05::c groups
+// on the interface.
+//
+// Should multicast discovery yield nothing, which is frequent behind Wi-Fi
+// access points and container bridges which drop multicast traffic, we fall
+// back to unicasting our M-SEARCH to the default gateway of the interface and
+// finally to probing the description URLs most commonly used by routers.
+func discoverIGDDescriptionURL(local localInterface) (u *url.URL, ok bool) {
+	network := "udp4"
+	if local.isIPv6() {
+		network = "udp6"
+	}
+
+	conn, err := ssdp.NewConn(network, local.iface, local.addr)
 	if err != nil {
-		panic("Programming error: Our UDPAddr is incorrect")
+		slog.Warn("Error occurred", "error", err)
+		return
 	}
+	defer conn.Close()
 
-	conn, err := net.ListenUDP("udp4", localBindAddr)
-	var timeout time.Duration = 4 * time.Second
-	if err == nil {
-		// For each device type, M-SEARCH for it, return the first one found
-		// As deviceTypes is sorted from most specific to least specific type
-		// returning the first should work fine.
+	// For each device type, M-SEARCH for it, return the first one found
+	// As deviceTypes is sorted from most specific to least specific type
+	// returning the first should work fine.
+	multicastAddrs := ssdp.MulticastAddrs(network, local.iface)
+	for i := range deviceTypes {
+		if u, ok = mSearch(conn, local, multicastAddrs, deviceTypes[i],
+			multicastTimeout); ok {
+			return
+		}
+	}
+
+	var gateways []gateway
+	for _, gw := range gatewaysFor(local.addr.IP) {
+		if (gw.IP.To4() == nil) == local.isIPv6() {
+			gateways = append(gateways, gw)
+		}
+	}
+	for _, gw := range gateways {
+		slog.Debug("Falling back to unicast M-SEARCH", "gateway", gw.IP)
+		gwAddr := &net.UDPAddr{IP: gw.IP, Port: ssdp.Port}
+		if gw.IP.IsLinkLocalUnicast() {
+			gwAddr.Zone = local.iface.Name
+		}
 		for i := range deviceTypes {
-			// We write our own request *à la main* as trying to use Go's
-			// standard library's HTTP package turns out to be require more
-			// code than writing the request by hand, because of the non-
-			// standard URL
-			requestString := fmt.Appendf(nil, format, ssdpIPv4Addr, ssdpPort,
-				deviceTypes[i], timeout/time.Second)
-			// Allocate a buffer for the response
-			buf := make([]byte, 1500)
-			// We want to timeout and move on to the next type after a couple of
-			// seconds
-			conn.SetDeadline(time.Now().Add(timeout))
-			// Send multicast request
-			conn.WriteToUDP(requestString, multicastAddr)
-			// Get a response; the above timeout is still in effect as it
-			// should be
-			n, addr, err := conn.ReadFromUDP(buf)
-			if err == nil {
-				// Ugly ugly ugly workaround for URL panic on *
-				adulteredReqStr := requestString
-				adulteredReqStr[9] = '/'
-				// Parse and interpret the response and break if successful
-				slog.Debug("Received bytes from address", "bytes", n, "address", addr)
-				req, err := http.ReadRequest(bufio.NewReader(bytes.NewReader(
-					adulteredReqStr)))
-				if err != nil {
-					// Failure to parse the request represents an assertion
-					// failure as we crafted the request ourselves and have
-					// ensured its validity
-					panic(err)
-				}
-				resp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(
-					buf[:n])), req)
-				if err == nil {
-					// We got something back, lets not leak it
-					defer resp.Body.Close()
-					slog.Debug("Discovered device returned", "headers", resp.Header)
-					// We extract the description URL returned in the Location
-					// header. The UPnP standard ensure
-					urls := resp.Header["Location"]
-					// We must check that the Location header exists as required
-					// by the standard to avoid panicking if we get a bad
-					// response missing a Location header.
-					if len(urls) > 0 {
-						// We have the location, bundle it up into a url.URL
-						// object and return it
-						u, err = url.Parse(urls[0])
-						ok = err == nil
-						return
-					} else {
-						slog.Warn("Response did not contain Location header", "headers", resp.Header)
-					}
-				} else {
-					slog.Warn("Error occurred", "error", err)
-				}
-			} else {
-				slog.Warn("Error occurred", "error", err)
+			if u, ok = mSearch(conn, local, []*net.UDPAddr{gwAddr},
+				deviceTypes[i], unicastTimeout); ok {
+				return
 			}
 		}
-	} else {
-		slog.Warn("Error occurred", "error", err)
 	}
+	for _, gw := range gateways {
+		if u, ok = probeDescriptionURLs(local, gw.IP); ok {
+			return
+		}
+	}
+
 	// If we get here we could not find any UPnP devices
 	return // ok is false by default, signaling this failure
 }
 
-func extractConnectionControlURL(d deviceElement) (upnptype, url string, ok bool) {
-	for i := 0; i < len(d.Services); i++ {
-		if serviceType := d.Services[i].ServiceType; serviceType == connectionTypeStringWANIP ||
-			serviceType == connectionTypeStringWANPPP {
-			return serviceType, d.Services[i].ControlURL, true
-		}
-	}
-	for i := 0; i < len(d.Devices); i++ {
-		if upnptype, url, ok = extractConnectionControlURL(d.Devices[i]); ok {
-			return
+const (
+	multicastTimeout = 4 * time.Second
+	unicastTimeout   = 2 * time.Second
+)
+
+// This function searches for the passed search target on dsts and returns the
+// description URL found in the Location header of the first response bearing
+// one.
+func mSearch(conn *ssdp.Conn, local localInterface, dsts []*net.UDPAddr,
+	st string, timeout time.Duration) (u *url.URL, ok bool) {
+	err := conn.Search(st, dsts, timeout, func(resp *ssdp.Response) bool {
+		slog.Debug("Discovered device returned", "headers", resp.Header)
+		// We must check that the Location header exists as required by the
+		// standard as some bad responses are missing one.
+		if resp.Location == "" {
+			slog.Warn("Response did not contain Location header", "headers", resp.Header)
+			return true
 		}
+		// We have the location, bundle it up into a url.URL object and
+		// return it
+		var err error
+		u, err = parseURLInZone(resp.Location, local.iface)
+		ok = err == nil
+		return !ok
+	})
+	if err != nil {
+		slog.Warn("Error occurred", "error", err)
 	}
 	return
 }
 
-func getConnectionControlURL(body []byte) (upnptype, url string, err error) {
-	var x deviceDescription
-	err = xml.Unmarshal(body, &x)
-	if err == nil {
-		var ok bool
-		upnptype, url, ok = extractConnectionControlURL(x.Device)
-		if !ok {
-			err = errors.New("Control URL not found")
-		} else {
-			// The URLs in the DeviceDescription elements are relative
-			url = x.URLBase + url
+// These are the ports and paths at which popular router firmwares serve their
+// root device description. They are probed in order on the default gateway as
+// a last resort when SSDP fails us entirely.
+var commonDescriptionURLs = []string{
+	"http://%s:5000/rootDesc.xml",      // MiniUPnPd (OpenWrt, pfSense…)
+	"http://%s:49000/igddesc.xml",      // AVM FRITZ!Box
+	"http://%s:1900/igd.xml",           // Linksys, Asus
+	"http://%s:52869/picsdesc.xml",     // Realtek SDK (TP-Link, D-Link…)
+	"http://%s:49152/gatedesc.xml",     // Broadcom SDK
+	"http://%s:80/upnp/IGD.xml",        // Belkin
+	"http://%s:2869/upnphost/udhisapi", // Windows ICS
+}
+
+// This function attempts to fetch each of commonDescriptionURLs from the
+// passed gateway and returns the first one which serves a device description
+// we can extract a connection control URL from.
+func probeDescriptionURLs(local localInterface, gw net.IP) (u *url.URL, ok bool) {
+	client := http.Client{Timeout: unicastTimeout}
+	host := gw.String()
+	if gw.To4() == nil {
+		if gw.IsLinkLocalUnicast() {
+			host += "%25" + local.iface.Name
+		}
+		host = "[" + host + "]"
+	}
+	for _, pattern := range commonDescriptionURLs {
+		candidate := fmt.Sprintf(pattern, host)
+		resp, err := client.Get(candidate)
+		if err != nil {
+			slog.Debug("Probing description URL failed", "url", candidate, "error", err)
+			continue
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil || resp.StatusCode != http.StatusOK {
+			continue
+		}
+		if _, _, err = getConnectionControlURL(body); err == nil {
+			u, err = url.Parse(candidate)
+			ok = err == nil
+			return
 		}
 	}
 	return
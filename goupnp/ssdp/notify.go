@@ -0,0 +1,350 @@
+package ssdp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"log/slog"
+
+	"golang.org/x/net/ipv4"
+	"golang.org/x/net/ipv6"
+)
+
+// These are the values of the NTS header of SSDP NOTIFY announcements
+const (
+	NTSAlive  = "ssdp:alive"
+	NTSByeBye = "ssdp:byebye"
+	NTSUpdate = "ssdp:update"
+)
+
+// This type describes an SSDP NOTIFY announcement, sent by devices when they
+// appear (ssdp:alive, repeated periodically), change (ssdp:update) or leave
+// (ssdp:byebye) the network.
+//
+// BootID and ConfigID are only sent by UPnP 1.1 devices and are -1 when
+// absent. A device whose BootID changed has rebooted and may have lost the
+// port mappings made on it.
+type Announcement struct {
+	NT       string
+	NTS      string
+	USN      string
+	Location string
+	// MaxAge is how long the device should be considered present without
+	// hearing from it again, zero for ssdp:byebye
+	MaxAge   time.Duration
+	BootID   int
+	ConfigID int
+
+	Header http.Header
+	// The address the announcement was received from and the name of the
+	// interface it was received on
+	Source *net.UDPAddr
+	Iface  string
+}
+
+func (self *Announcement) String() string {
+	return self.NTS + " " + self.USN + " " + self.Location
+}
+
+// This function parses the passed datagram as an SSDP NOTIFY announcement.
+func ParseAnnouncement(buf []byte) (*Announcement, error) {
+	req, err := http.ReadRequest(bufio.NewReader(bytes.NewReader(buf)))
+	if err != nil {
+		return nil, err
+	}
+	if req.Method != "NOTIFY" {
+		return nil, errors.New("Not a NOTIFY request: " + req.Method)
+	}
+	announcement := Announcement{
+		NT:       req.Header.Get("NT"),
+		NTS:      req.Header.Get("NTS"),
+		USN:      req.Header.Get("USN"),
+		Location: req.Header.Get("Location"),
+		MaxAge:   parseMaxAge(req.Header.Get("Cache-Control")),
+		BootID:   parseIntHeader(req.Header, "BOOTID.UPNP.ORG"),
+		ConfigID: parseIntHeader(req.Header, "CONFIGID.UPNP.ORG"),
+		Header:   req.Header,
+	}
+	if announcement.NTS == "" || announcement.USN == "" {
+		return nil, errors.New("NOTIFY request is missing NTS or USN")
+	}
+	return &announcement, nil
+}
+
+// This type listens passively for SSDP announcements on the multicast groups
+// it was made to Join(). Announcements are sent on the Announcements channel,
+// which is closed once Close() has been called.
+//
+// A single socket per address family receives the announcements of every
+// interface joined, each being labelled with the interface it arrived on, so
+// that joining several interfaces does not deliver announcements twice. Where
+// the platform cannot report the interface a datagram arrived on, as on
+// Windows, there is instead a socket per interface and group.
+type Listener struct {
+	Announcements chan *Announcement
+
+	mutex sync.Mutex
+	// The socket shared by the interfaces joined of each network, nil if it
+	// could not be opened, the sockets of each interface then being kept in
+	// ifaceConns
+	conns      map[string]groupConn
+	ifaceConns []*net.UDPConn
+	// The names of the interfaces joined by network and index, datagrams
+	// received on any other interface are dropped
+	ifaces map[string]map[int]string
+	closed bool
+	stop   chan struct{}
+	wg     sync.WaitGroup
+}
+
+// This function returns a Listener which has not joined any group yet.
+func NewListener() *Listener {
+	return &Listener{
+		Announcements: make(chan *Announcement, 16),
+		conns:         make(map[string]groupConn),
+		ifaces:        make(map[string]map[int]string),
+		stop:          make(chan struct{}),
+	}
+}
+
+// This method joins the SSDP multicast groups of network, "udp4" or "udp6",
+// on the passed interface. An error is returned if none of the groups could
+// be joined.
+func (self *Listener) Join(network string, iface *net.Interface) error {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	if self.closed {
+		return net.ErrClosed
+	}
+
+	conn := self.conn(network)
+	if conn == nil {
+		return self.listenOn(network, iface)
+	}
+	var errs []error
+	joined := false
+	for _, group := range MulticastAddrs(network, iface) {
+		if err := conn.JoinGroup(iface, &net.UDPAddr{IP: group.IP}); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		joined = true
+	}
+	if !joined {
+		return errors.Join(errs...)
+	}
+	self.ifaces[network][iface.Index] = iface.Name
+	return nil
+}
+
+// This method returns the socket receiving the announcements of network,
+// opening it on first use, or nil if it cannot be opened. The mutex must be
+// held.
+func (self *Listener) conn(network string) groupConn {
+	if conn, ok := self.conns[network]; ok {
+		return conn
+	}
+	self.ifaces[network] = make(map[int]string)
+	conn, err := listenGroup(network)
+	if err != nil {
+		slog.Debug("Listening on each interface separately", "network", network,
+			"error", err)
+		self.conns[network] = nil
+		return nil
+	}
+	self.conns[network] = conn
+	self.wg.Add(1)
+	go self.receive(network, conn)
+	return conn
+}
+
+// This function opens a socket receiving the SSDP datagrams of network on
+// every interface, reporting the interface each arrived on.
+func listenGroup(network string) (groupConn, error) {
+	// The socket is bound to the wildcard address as binding to the
+	// link-local group would tie it to a single interface
+	lc := net.ListenConfig{Control: reuseAddr}
+	c, err := lc.ListenPacket(context.Background(), network,
+		net.JoinHostPort("", strconv.Itoa(Port)))
+	if err != nil {
+		return nil, err
+	}
+	conn, err := newGroupConn(network, c)
+	if err != nil {
+		c.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+// This method listens for the announcements of network on the passed
+// interface alone, with a socket per group. It is only used where the shared
+// socket cannot be opened, as on Linux such sockets also receive the
+// datagrams of the groups joined on other interfaces. The mutex must be held.
+func (self *Listener) listenOn(network string, iface *net.Interface) error {
+	var errs []error
+	joined := false
+	for _, group := range MulticastAddrs(network, iface) {
+		c, err := net.ListenMulticastUDP(network, iface, group)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		self.ifaceConns = append(self.ifaceConns, c)
+		self.wg.Add(1)
+		go self.receive(network, ifaceConn{c, iface.Index})
+		joined = true
+	}
+	if !joined {
+		return errors.Join(errs...)
+	}
+	self.ifaces[network][iface.Index] = iface.Name
+	return nil
+}
+
+// This method stops listening, after which the Announcements channel is
+// closed. Calling it more than once has no effect.
+func (self *Listener) Close() error {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	if self.closed {
+		return nil
+	}
+	self.closed = true
+
+	close(self.stop)
+	var errs []error
+	for _, conn := range self.conns {
+		if conn != nil {
+			errs = append(errs, conn.Close())
+		}
+	}
+	for _, c := range self.ifaceConns {
+		errs = append(errs, c.Close())
+	}
+	go func() {
+		self.wg.Wait()
+		close(self.Announcements)
+	}()
+	return errors.Join(errs...)
+}
+
+// This method returns the name of the interface of the passed index if it was
+// joined for network, or false.
+func (self *Listener) joined(network string, index int) (string, bool) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	name, ok := self.ifaces[network][index]
+	return name, ok
+}
+
+func (self *Listener) receive(network string, conn announcementConn) {
+	defer self.wg.Done()
+	buf := make([]byte, 2048)
+	for {
+		n, index, dst, addr, err := conn.readFrom(buf)
+		if err != nil {
+			select {
+			case <-self.stop:
+			default:
+				slog.Warn("Error occurred", "error", err)
+			}
+			return
+		}
+		// The socket also receives unicast datagrams sent to the SSDP port
+		// and, on Linux, the group datagrams of interfaces other processes
+		// joined
+		iface, ok := self.joined(network, index)
+		if !ok || dst != nil && !dst.IsMulticast() {
+			continue
+		}
+		announcement, err := ParseAnnouncement(buf[:n])
+		if err != nil {
+			// M-SEARCH requests of other control points are also sent to the
+			// group, they are of no interest to us
+			slog.Debug("Ignoring SSDP datagram", "address", addr, "error", err)
+			continue
+		}
+		announcement.Source, _ = addr.(*net.UDPAddr)
+		announcement.Iface = iface
+		select {
+		case self.Announcements <- announcement:
+		case <-self.stop:
+			return
+		}
+	}
+}
+
+// This interface abstracts over the sockets announcements are read from.
+type announcementConn interface {
+	// This method reads a datagram along with the index of the interface it
+	// arrived on and its destination address, nil if unknown
+	readFrom(buf []byte) (n, index int, dst net.IP, src net.Addr, err error)
+}
+
+// This interface abstracts over the IPv4 and IPv6 packet connections of
+// golang.org/x/net, which alone report the interface a datagram arrived on.
+type groupConn interface {
+	announcementConn
+	JoinGroup(iface *net.Interface, group net.Addr) error
+	Close() error
+}
+
+// This function wraps the passed socket of network into a groupConn which
+// reports the interface and destination of every datagram.
+func newGroupConn(network string, c net.PacketConn) (groupConn, error) {
+	if network == "udp6" {
+		conn := ipv6.NewPacketConn(c)
+		err := conn.SetControlMessage(ipv6.FlagInterface|ipv6.FlagDst, true)
+		return ipv6GroupConn{conn}, err
+	}
+	conn := ipv4.NewPacketConn(c)
+	err := conn.SetControlMessage(ipv4.FlagInterface|ipv4.FlagDst, true)
+	return ipv4GroupConn{conn}, err
+}
+
+type ipv4GroupConn struct {
+	*ipv4.PacketConn
+}
+
+func (self ipv4GroupConn) readFrom(buf []byte) (n, index int, dst net.IP,
+	src net.Addr, err error) {
+	n, cm, src, err := self.ReadFrom(buf)
+	if cm != nil {
+		index, dst = cm.IfIndex, cm.Dst
+	}
+	return
+}
+
+type ipv6GroupConn struct {
+	*ipv6.PacketConn
+}
+
+func (self ipv6GroupConn) readFrom(buf []byte) (n, index int, dst net.IP,
+	src net.Addr, err error) {
+	n, cm, src, err := self.ReadFrom(buf)
+	if cm != nil {
+		index, dst = cm.IfIndex, cm.Dst
+	}
+	return
+}
+
+// This type reads the datagrams of a socket listening on a single interface,
+// which they are all reported as having arrived on.
+type ifaceConn struct {
+	*net.UDPConn
+	index int
+}
+
+func (self ifaceConn) readFrom(buf []byte) (n, index int, dst net.IP,
+	src net.Addr, err error) {
+	n, src, err = self.ReadFrom(buf)
+	return n, self.index, nil, src, err
+}
@@ -0,0 +1,185 @@
+package ssdp
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/net/ipv4"
+)
+
+func TestAnnouncementParsing(t *testing.T) {
+	alive := strings.ReplaceAll(`NOTIFY * HTTP/1.1
+HOST: 239.255.255.250:1900
+CACHE-CONTROL: max-age = 1800
+LOCATION: http://192.168.1.1:5000/rootDesc.xml
+SERVER: OpenWRT/OpenWrt UPnP/1.1 MiniUPnPd/2.2.1
+NT: urn:schemas-upnp-org:device:InternetGatewayDevice:1
+USN: uuid:a1b2c3d4-0000-0000-0000-000000000000::urn:schemas-upnp-org:device:InternetGatewayDevice:1
+NTS: ssdp:alive
+OPT: "http://schemas.upnp.org/upnp/1/0/"; ns=01
+01-NLS: 1673452211
+BOOTID.UPNP.ORG: 1673452211
+CONFIGID.UPNP.ORG: 1337
+
+`, "\n", "\r\n")
+
+	announcement, err := ParseAnnouncement([]byte(alive))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if announcement.NTS != NTSAlive ||
+		announcement.NT != "urn:schemas-upnp-org:device:InternetGatewayDevice:1" ||
+		announcement.Location != "http://192.168.1.1:5000/rootDesc.xml" ||
+		announcement.MaxAge != 1800*time.Second ||
+		announcement.BootID != 1673452211 || announcement.ConfigID != 1337 {
+		t.Errorf("Announcement incorrectly parsed as %+v", announcement)
+	}
+
+	byebye := strings.ReplaceAll(`NOTIFY * HTTP/1.1
+HOST: 239.255.255.250:1900
+NT: upnp:rootdevice
+NTS: ssdp:byebye
+USN: uuid:a1b2c3d4-0000-0000-0000-000000000000::upnp:rootdevice
+
+`, "\n", "\r\n")
+
+	announcement, err = ParseAnnouncement([]byte(byebye))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if announcement.NTS != NTSByeBye || announcement.MaxAge != 0 ||
+		announcement.BootID != -1 || announcement.ConfigID != -1 {
+		t.Errorf("Announcement incorrectly parsed as %+v", announcement)
+	}
+
+	search := strings.ReplaceAll(`M-SEARCH * HTTP/1.1
+HOST: 239.255.255.250:1900
+MAN: "ssdp:discover"
+ST: ssdp:all
+MX: 2
+
+`, "\n", "\r\n")
+	if _, err = ParseAnnouncement([]byte(search)); err == nil {
+		t.Error("M-SEARCH request incorrectly parsed as an announcement")
+	}
+}
+
+// This function returns the up interfaces with an IPv4 address, on which the
+// IPv4 SSDP group may be joined.
+func ipv4Interfaces(t *testing.T) (ret []*net.Interface) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range ifaces {
+		if ifaces[i].Flags&net.FlagUp == 0 {
+			continue
+		}
+		addrs, _ := ifaces[i].Addrs()
+		for _, addr := range addrs {
+			if ipNet, ok := addr.(*net.IPNet); ok && ipNet.IP.To4() != nil {
+				ret = append(ret, &ifaces[i])
+				break
+			}
+		}
+	}
+	return
+}
+
+// This function sends a byebye announcement of the passed USN to the IPv4
+// SSDP group on the passed interface, skipping the test if it cannot.
+func sendNotify(t *testing.T, iface *net.Interface, usn string) {
+	t.Helper()
+	c, err := net.ListenPacket("udp4", "0.0.0.0:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	sender := ipv4.NewPacketConn(c)
+	sender.SetMulticastLoopback(true)
+	if err := sender.SetMulticastInterface(iface); err != nil {
+		t.Fatal(err)
+	}
+	notify := strings.ReplaceAll(fmt.Sprintf(`NOTIFY * HTTP/1.1
+HOST: 239.255.255.250:1900
+NT: upnp:rootdevice
+NTS: ssdp:byebye
+USN: %s
+
+`, usn), "\n", "\r\n")
+	group := &net.UDPAddr{IP: net.ParseIP(IPv4Addr), Port: Port}
+	if _, err := sender.WriteTo([]byte(notify), nil, group); err != nil {
+		t.Skipf("Cannot send to the SSDP group on %s: %v", iface.Name, err)
+	}
+}
+
+// This function returns the announcements of the passed USN the listener
+// receives within half a second.
+func receiveAnnouncements(listener *Listener, usn string) (received []*Announcement) {
+	timeout := time.After(500 * time.Millisecond)
+	for {
+		select {
+		case announcement := <-listener.Announcements:
+			if announcement.USN == usn {
+				received = append(received, announcement)
+			}
+		case <-timeout:
+			return
+		}
+	}
+}
+
+func TestListenerJoinsSeveralInterfaces(t *testing.T) {
+	ifaces := ipv4Interfaces(t)
+	if len(ifaces) < 2 {
+		t.Skip("Fewer than two interfaces with an IPv4 address")
+	}
+	ifaces = ifaces[:2]
+
+	listener := NewListener()
+	defer listener.Close()
+	for _, iface := range ifaces {
+		if err := listener.Join("udp4", iface); err != nil {
+			t.Skipf("Cannot join the SSDP group on %s: %v", iface.Name, err)
+		}
+	}
+
+	for i, iface := range ifaces {
+		usn := fmt.Sprintf("uuid:test-%d::upnp:rootdevice", i)
+		sendNotify(t, iface, usn)
+		received := receiveAnnouncements(listener, usn)
+		if len(received) != 1 || received[0].Iface != iface.Name {
+			for _, announcement := range received {
+				t.Logf("Received on %s", announcement.Iface)
+			}
+			t.Errorf("Announcement sent on %s received %d times", iface.Name, len(received))
+		}
+	}
+}
+
+func TestListenerSocketPerInterface(t *testing.T) {
+	ifaces := ipv4Interfaces(t)
+	if len(ifaces) == 0 {
+		t.Skip("No interface with an IPv4 address")
+	}
+	iface := ifaces[0]
+
+	// As on platforms where the shared socket cannot be opened
+	listener := NewListener()
+	defer listener.Close()
+	listener.conns["udp4"] = nil
+	listener.ifaces["udp4"] = make(map[int]string)
+	if err := listener.Join("udp4", iface); err != nil {
+		t.Skipf("Cannot join the SSDP group on %s: %v", iface.Name, err)
+	}
+
+	const usn = "uuid:test::upnp:rootdevice"
+	sendNotify(t, iface, usn)
+	received := receiveAnnouncements(listener, usn)
+	if len(received) != 1 || received[0].Iface != iface.Name {
+		t.Errorf("Announcement sent on %s received as %v", iface.Name, received)
+	}
+}
@@ -0,0 +1,53 @@
+package ssdp
+
+import (
+	"net"
+	"syscall"
+
+	"log/slog"
+)
+
+// This function binds the socket to the passed interface with SO_BINDTODEVICE
+// and selects it for outgoing multicast with IP_MULTICAST_IF or
+// IPV6_MULTICAST_IF depending on the address family, so that SSDP
+// traffic leaves through the intended device even when several share a subnet
+// or the routing table would send it elsewhere.
+//
+// SO_BINDTODEVICE requires CAP_NET_RAW on kernels older than 5.7, failing to
+// set it is therefore only logged as we still have the source address binding
+// to fall back on.
+func bindToInterface(c syscall.RawConn, iface *net.Interface, ip net.IP) error {
+	var sockErr error
+	err := c.Control(func(fd uintptr) {
+		if err := syscall.SetsockoptString(int(fd), syscall.SOL_SOCKET,
+			syscall.SO_BINDTODEVICE, iface.Name); err != nil {
+			slog.Debug("Could not bind to device", "iface", iface.Name, "error", err)
+		}
+		if ip4 := ip.To4(); ip4 != nil {
+			sockErr = syscall.SetsockoptInet4Addr(int(fd), syscall.IPPROTO_IP,
+				syscall.IP_MULTICAST_IF, [4]byte(ip4))
+		} else {
+			sockErr = syscall.SetsockoptInt(int(fd), syscall.IPPROTO_IPV6,
+				syscall.IPV6_MULTICAST_IF, iface.Index)
+		}
+	})
+	if err != nil {
+		return err
+	}
+	return sockErr
+}
+
+// This function sets SO_REUSEADDR on the socket about to be bound, so that
+// the SSDP port may be shared with the other control points and SSDP daemons
+// of the host.
+func reuseAddr(network, address string, c syscall.RawConn) error {
+	var sockErr error
+	err := c.Control(func(fd uintptr) {
+		sockErr = syscall.SetsockoptInt(int(fd), syscall.SOL_SOCKET,
+			syscall.SO_REUSEADDR, 1)
+	})
+	if err != nil {
+		return err
+	}
+	return sockErr
+}
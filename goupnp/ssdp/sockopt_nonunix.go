@@ -0,0 +1,13 @@
+//go:build !unix
+
+package ssdp
+
+import (
+	"syscall"
+)
+
+// The shared socket is not arranged for outside of Unix, where Listener falls
+// back on net.ListenMulticastUDP, which sets the options the platform needs.
+func reuseAddr(network, address string, c syscall.RawConn) error {
+	return nil
+}
@@ -0,0 +1,14 @@
+//go:build !linux
+
+package ssdp
+
+import (
+	"net"
+	"syscall"
+)
+
+// Binding to a device is only supported on Linux for the time being,
+// elsewhere we rely solely on binding to the interface's source address.
+func bindToInterface(c syscall.RawConn, iface *net.Interface, ip net.IP) error {
+	return nil
+}
@@ -0,0 +1,28 @@
+//go:build unix && !linux && !solaris
+
+package ssdp
+
+import (
+	"syscall"
+)
+
+// This function sets SO_REUSEADDR and SO_REUSEPORT on the socket about to be
+// bound, the BSDs requiring the latter for several sockets to receive the
+// datagrams of a multicast group, so that the SSDP port may be shared with
+// the other control points and SSDP daemons of the host, such as
+// mDNSResponder on macOS.
+func reuseAddr(network, address string, c syscall.RawConn) error {
+	var sockErr error
+	err := c.Control(func(fd uintptr) {
+		sockErr = syscall.SetsockoptInt(int(fd), syscall.SOL_SOCKET,
+			syscall.SO_REUSEADDR, 1)
+		if sockErr == nil {
+			sockErr = syscall.SetsockoptInt(int(fd), syscall.SOL_SOCKET,
+				syscall.SO_REUSEPORT, 1)
+		}
+	})
+	if err != nil {
+		return err
+	}
+	return sockErr
+}
@@ -0,0 +1,21 @@
+package ssdp
+
+import (
+	"syscall"
+)
+
+// This function sets SO_REUSEADDR on the socket about to be bound, which
+// alone lets several sockets share a multicast port on Solaris, so that the
+// SSDP port may be shared with the other control points and SSDP daemons of
+// the host.
+func reuseAddr(network, address string, c syscall.RawConn) error {
+	var sockErr error
+	err := c.Control(func(fd uintptr) {
+		sockErr = syscall.SetsockoptInt(int(fd), syscall.SOL_SOCKET,
+			syscall.SO_REUSEADDR, 1)
+	})
+	if err != nil {
+		return err
+	}
+	return sockErr
+}
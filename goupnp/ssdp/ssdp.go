@@ -0,0 +1,250 @@
+// A minimal implementation of the Simple Service Discovery Protocol used by
+// UPnP devices to advertise themselves, usable to find any kind of device
+// rather than only the IGDs the parent goupnp package is concerned with.
+package ssdp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"log/slog"
+)
+
+const (
+	IPv4Addr          = "239.255.255.250"
+	IPv6LinkLocalAddr = "ff02::c"
+	IPv6SiteLocalAddr = "ff05::c"
+	Port              = 1900
+
+	// Search targets matching respectively every device and service and
+	// every root device
+	All        = "ssdp:all"
+	RootDevice = "upnp:rootdevice"
+
+	format = "M-SEARCH * HTTP/1.1\r\n" +
+		"HOST: %s:%d\r\n" +
+		"ST: %s\r\n" +
+		"MAN: \"ssdp:discover\"\r\n" +
+		"MX: %d\r\n" +
+		"\r\n"
+)
+
+// This function returns the SSDP multicast groups of the passed network,
+// "udp4" or "udp6", scoped to the passed interface where relevant.
+func MulticastAddrs(network string, iface *net.Interface) []*net.UDPAddr {
+	if network == "udp6" {
+		var zone string
+		if iface != nil {
+			zone = iface.Name
+		}
+		return []*net.UDPAddr{
+			{IP: net.ParseIP(IPv6LinkLocalAddr), Port: Port, Zone: zone},
+			{IP: net.ParseIP(IPv6SiteLocalAddr), Port: Port, Zone: zone},
+		}
+	}
+	return []*net.UDPAddr{{IP: net.ParseIP(IPv4Addr), Port: Port}}
+}
+
+// This type describes a response to an M-SEARCH request. Every header is kept
+// in Header, the most useful ones are additionally parsed into the other
+// fields.
+//
+// BootID and ConfigID are only sent by UPnP 1.1 devices and are -1 when
+// absent.
+type Response struct {
+	ST       string
+	USN      string
+	Server   string
+	Location string
+	// Ext is true if the mandatory, albeit empty, EXT header was present
+	Ext      bool
+	MaxAge   time.Duration
+	BootID   int
+	ConfigID int
+
+	Header http.Header
+	// The address the response was received from
+	Source *net.UDPAddr
+}
+
+func (self *Response) String() string {
+	return self.ST + " " + self.USN + " " + self.Location
+}
+
+// This function parses the passed datagram as a response to an M-SEARCH.
+func ParseResponse(buf []byte) (*Response, error) {
+	resp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(buf)), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Unexpected status %q", resp.Status)
+	}
+	_, ext := resp.Header["Ext"]
+	return &Response{
+		ST:       resp.Header.Get("ST"),
+		USN:      resp.Header.Get("USN"),
+		Server:   resp.Header.Get("Server"),
+		Location: resp.Header.Get("Location"),
+		Ext:      ext,
+		MaxAge:   parseMaxAge(resp.Header.Get("Cache-Control")),
+		BootID:   parseIntHeader(resp.Header, "BOOTID.UPNP.ORG"),
+		ConfigID: parseIntHeader(resp.Header, "CONFIGID.UPNP.ORG"),
+		Header:   resp.Header,
+	}, nil
+}
+
+// This type wraps the UDP socket M-SEARCH requests are sent from and their
+// responses received on. Use NewConn() to obtain one.
+type Conn struct {
+	conn *net.UDPConn
+}
+
+// This function opens a socket for network, "udp4" or "udp6", bound to the
+// passed local address and, when iface is not nil, to the device itself so
+// that requests leave through the intended interface even when several share
+// a subnet or the routing table would send them elsewhere.
+func NewConn(network string, iface *net.Interface, local *net.UDPAddr) (*Conn, error) {
+	lc := net.ListenConfig{
+		Control: func(network, address string, c syscall.RawConn) error {
+			if iface == nil {
+				return nil
+			}
+			return bindToInterface(c, iface, local.IP)
+		},
+	}
+	conn, err := lc.ListenPacket(context.Background(), network, local.String())
+	if err != nil {
+		return nil, err
+	}
+	return &Conn{conn.(*net.UDPConn)}, nil
+}
+
+func (self *Conn) Close() error {
+	return self.conn.Close()
+}
+
+// This function returns the passed IP address in the form expected in the
+// HOST header of SSDP requests, that is with IPv6 addresses in brackets and
+// without any zone.
+func host(ip net.IP) string {
+	if ip.To4() == nil {
+		return "[" + strings.ToUpper(ip.String()) + "]"
+	}
+	return ip.String()
+}
+
+// This function returns an M-SEARCH request for the passed search target
+// addressed to dst. The MX header is set to wait, in seconds, which must
+// therefore be at least a second.
+func Request(dst *net.UDPAddr, st string, wait time.Duration) []byte {
+	// We write our own request *à la main* as trying to use Go's standard
+	// library's HTTP package turns out to be require more code than writing
+	// the request by hand, because of the non-standard URL
+	return fmt.Appendf(nil, format, host(dst.IP), dst.Port, st,
+		wait/time.Second)
+}
+
+// This method sends an M-SEARCH for the passed search target to each of dsts,
+// which may either be SSDP multicast groups or a device we wish to query
+// directly, and calls handle with every response received until wait expires
+// or handle returns false.
+//
+// Devices wait a random delay of up to wait before answering multicast
+// requests, waits shorter than 3 seconds are therefore unreasonable.
+// Responses which cannot be parsed are skipped.
+func (self *Conn) Search(st string, dsts []*net.UDPAddr, wait time.Duration,
+	handle func(*Response) bool) error {
+	self.conn.SetDeadline(time.Now().Add(wait))
+	for _, dst := range dsts {
+		if _, err := self.conn.WriteToUDP(Request(dst, st, wait), dst); err != nil {
+			slog.Debug("Failed to send M-SEARCH", "destination", dst, "error", err)
+		}
+	}
+	// Allocate a buffer for the responses
+	buf := make([]byte, 2048)
+	for {
+		n, addr, err := self.conn.ReadFromUDP(buf)
+		if err != nil {
+			// Reaching the deadline is how every search ends
+			if errors.Is(err, os.ErrDeadlineExceeded) {
+				return nil
+			}
+			return err
+		}
+		slog.Debug("Received bytes from address", "bytes", n, "address", addr)
+		resp, err := ParseResponse(buf[:n])
+		if err != nil {
+			slog.Debug("Ignoring SSDP datagram", "address", addr, "error", err)
+			continue
+		}
+		resp.Source = addr
+		if !handle(resp) {
+			return nil
+		}
+	}
+}
+
+// This method behaves as Search() but collects every response received until
+// wait expires.
+func (self *Conn) SearchAll(st string, dsts []*net.UDPAddr,
+	wait time.Duration) (ret []*Response, err error) {
+	err = self.Search(st, dsts, wait, func(resp *Response) bool {
+		ret = append(ret, resp)
+		return true
+	})
+	return
+}
+
+// This function searches the SSDP multicast groups of the family of local for
+// the passed search target, ssdp:all finding every device and service, and
+// returns every response received until wait expires. iface may be nil, in
+// which case the socket is only bound to the local address.
+func Search(st string, iface *net.Interface, local *net.UDPAddr,
+	wait time.Duration) ([]*Response, error) {
+	network := "udp4"
+	if local.IP.To4() == nil {
+		network = "udp6"
+	}
+	conn, err := NewConn(network, iface, local)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	return conn.SearchAll(st, MulticastAddrs(network, iface), wait)
+}
+
+// This function extracts the max-age directive of the passed CACHE-CONTROL
+// header value, tolerating the whitespace some devices put around '='.
+func parseMaxAge(cacheControl string) time.Duration {
+	for _, directive := range strings.Split(cacheControl, ",") {
+		name, value, ok := strings.Cut(directive, "=")
+		if ok && strings.EqualFold(strings.TrimSpace(name), "max-age") {
+			if seconds, err := strconv.Atoi(strings.TrimSpace(value)); err == nil {
+				return time.Duration(seconds) * time.Second
+			}
+		}
+	}
+	return 0
+}
+
+// This function returns the value of the passed header as an integer, or -1
+// if it is absent or malformed.
+func parseIntHeader(header http.Header, name string) int {
+	value, err := strconv.Atoi(strings.TrimSpace(header.Get(name)))
+	if err != nil {
+		return -1
+	}
+	return value
+}
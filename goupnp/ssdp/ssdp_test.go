@@ -0,0 +1,63 @@
+package ssdp
+
+import (
+	"bufio"
+	"bytes"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRequestCompatibility(t *testing.T) {
+	for _, network := range []string{"udp4", "udp6"} {
+		for _, dst := range MulticastAddrs(network, nil) {
+			requestString := Request(dst, RootDevice, 2*time.Second)
+
+			req, err := http.ReadRequest(bufio.NewReader(bytes.NewReader(
+				requestString)))
+			if err != nil {
+				t.Error(err, req)
+			} else if req.Method != "M-SEARCH" || req.Header.Get("ST") != RootDevice ||
+				req.Header.Get("MX") != "2" {
+				t.Errorf("Request incorrectly formatted as %q", requestString)
+			}
+		}
+	}
+
+	requestString := Request(&net.UDPAddr{IP: net.ParseIP(IPv6LinkLocalAddr),
+		Port: Port, Zone: "eth0"}, All, 3*time.Second)
+	if !bytes.Contains(requestString, []byte("HOST: [FF02::C]:1900\r\n")) {
+		t.Errorf("Request incorrectly formatted as %q", requestString)
+	}
+}
+
+func TestResponseParsing(t *testing.T) {
+	response := strings.ReplaceAll(`HTTP/1.1 200 OK
+CACHE-CONTROL: max-age=120
+ST: urn:schemas-upnp-org:device:InternetGatewayDevice:1
+USN: uuid:a1b2c3d4-0000-0000-0000-000000000000::urn:schemas-upnp-org:device:InternetGatewayDevice:1
+EXT:
+SERVER: OpenWRT/OpenWrt UPnP/1.1 MiniUPnPd/2.2.1
+LOCATION: http://192.168.1.1:5000/rootDesc.xml
+OPT: "http://schemas.upnp.org/upnp/1/0/"; ns=01
+01-NLS: 1673452211
+BOOTID.UPNP.ORG: 1673452211
+CONFIGID.UPNP.ORG: 1337
+
+`, "\n", "\r\n")
+
+	resp, err := ParseResponse([]byte(response))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.ST != "urn:schemas-upnp-org:device:InternetGatewayDevice:1" ||
+		!strings.HasPrefix(resp.USN, "uuid:a1b2c3d4") ||
+		resp.Server != "OpenWRT/OpenWrt UPnP/1.1 MiniUPnPd/2.2.1" ||
+		resp.Location != "http://192.168.1.1:5000/rootDesc.xml" || !resp.Ext ||
+		resp.MaxAge != 120*time.Second || resp.BootID != 1673452211 ||
+		resp.ConfigID != 1337 || resp.Header.Get("01-NLS") != "1673452211" {
+		t.Errorf("Response incorrectly parsed as %+v", resp)
+	}
+}
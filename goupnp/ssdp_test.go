@@ -1,16 +1,10 @@
 package goupnp
 
 import (
-	"bufio"
-	"bytes"
-	"fmt"
-	"net/http"
 	"testing"
-	"time"
 )
 
-func TestDescriptionParsing(t *testing.T) {
-	const belkinDescription string = `<?xml version="1.0"?>
+const belkinDescription string = `<?xml version="1.0"?>
 <root xmlns="urn:schemas-upnp-org:device-1-0">
 	<specVersion>
 		<major>1</major>
@@ -91,21 +85,9 @@ func TestDescriptionParsing(t *testing.T) {
 </root>
 `
 
+func TestDescriptionParsing(t *testing.T) {
 	upnptype, url, err := getConnectionControlURL([]byte(belkinDescription))
 	if upnptype != connectionTypeStringWANIP || url != "http://192.168.2.1:80/upnp/service/WANIPConnection" || err != nil {
 		t.Errorf("Type: %v, URL: %v, Error: %v", upnptype, url, err)
 	}
 }
-
-func TestRequestCompatibility(t *testing.T) {
-	requestString := fmt.Appendf(nil, format, ssdpIPv4Addr, ssdpPort,
-		deviceTypes[0], 2*time.Second)
-	requestString[9] = '/'
-
-	req, err := http.ReadRequest(bufio.NewReader(bytes.NewReader(
-		requestString)))
-	if err != nil {
-		t.Error(err, req)
-	}
-
-}
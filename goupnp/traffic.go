@@ -0,0 +1,178 @@
+package goupnp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// The error returned by the traffic statistics methods when the IGD does not
+// implement the WANCommonInterfaceConfig service.
+var ErrNoCommonInterfaceConfig = errors.New("IGD does not provide WANCommonInterfaceConfig")
+
+// This type describes the physical WAN link of an IGD.
+type LinkProperties struct {
+	// One of DSL, POTS, Cable, Ethernet or Other
+	AccessType string
+	// The maximum bit rates of the link in bits per second
+	MaxBitRateUp   uint32
+	MaxBitRateDown uint32
+	// One of Up, Down, Initializing or Unavailable
+	PhysicalLinkStatus string
+}
+
+// This type holds the traffic counters of the WAN link of an IGD at a point
+// in time. The counters are 32-bit and wrap around, which takes less than an
+// hour for bytes on a 10 Mbit/s link, hence are best turned into rates by a
+// TrafficSampler.
+type TrafficCounters struct {
+	BytesSent       uint32
+	BytesReceived   uint32
+	PacketsSent     uint32
+	PacketsReceived uint32
+	// Whether the IGD reported the packet counters, which are zero otherwise
+	HasPackets bool
+	Time       time.Time
+}
+
+// This type describes the throughput of the WAN link of an IGD over Interval.
+type TrafficRates struct {
+	Interval                 time.Duration
+	BytesSentPerSecond       float64
+	BytesReceivedPerSecond   float64
+	PacketsSentPerSecond     float64
+	PacketsReceivedPerSecond float64
+	// Whether both samples had packet counters, the packet rates are zero
+	// otherwise
+	HasPackets bool
+}
+
+func (self *TrafficRates) String() string {
+	if !self.HasPackets {
+		return fmt.Sprintf("up %.0f B/s down %.0f B/s over %v",
+			self.BytesSentPerSecond, self.BytesReceivedPerSecond, self.Interval)
+	}
+	return fmt.Sprintf("up %.0f B/s (%.0f p/s) down %.0f B/s (%.0f p/s) over %v",
+		self.BytesSentPerSecond, self.PacketsSentPerSecond,
+		self.BytesReceivedPerSecond, self.PacketsReceivedPerSecond, self.Interval)
+}
+
+// This method returns a client for the WANCommonInterfaceConfig service of the
+// WANDevice carrying the connection the IGD is bound to.
+func (self *IGD) CommonInterfaceConfig() (*WANCommonInterfaceConfig1, error) {
+	service := self.serviceNearConnection(WANCommonInterfaceConfig1ServiceType)
+	if service == nil {
+		return nil, ErrNoCommonInterfaceConfig
+	}
+	return &WANCommonInterfaceConfig1{igd: self, Service: service}, nil
+}
+
+// This method fetches the properties of the physical WAN link of the IGD.
+func (self *IGD) GetCommonLinkProperties(ctx context.Context) (*LinkProperties, error) {
+	client, err := self.CommonInterfaceConfig()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.GetCommonLinkProperties(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &LinkProperties{
+		AccessType:         resp.NewWANAccessType,
+		MaxBitRateUp:       resp.NewLayer1UpstreamMaxBitRate,
+		MaxBitRateDown:     resp.NewLayer1DownstreamMaxBitRate,
+		PhysicalLinkStatus: resp.NewPhysicalLinkStatus,
+	}, nil
+}
+
+// This method fetches the traffic counters of the WAN link of the IGD. The
+// byte counters are required, whereas the packet counters are left zero, and
+// HasPackets unset, if the IGD fails to report either of them, as is often the
+// case.
+func (self *IGD) GetTrafficCounters(ctx context.Context) (*TrafficCounters, error) {
+	client, err := self.CommonInterfaceConfig()
+	if err != nil {
+		return nil, err
+	}
+	var counters TrafficCounters
+	sent, err := client.GetTotalBytesSent(ctx)
+	if err != nil {
+		return nil, err
+	}
+	received, err := client.GetTotalBytesReceived(ctx)
+	if err != nil {
+		return nil, err
+	}
+	counters.Time = time.Now()
+	counters.BytesSent = sent.NewTotalBytesSent
+	counters.BytesReceived = received.NewTotalBytesReceived
+
+	packetsSent, err := client.GetTotalPacketsSent(ctx)
+	if err != nil {
+		return &counters, nil
+	}
+	packetsReceived, err := client.GetTotalPacketsReceived(ctx)
+	if err != nil {
+		return &counters, nil
+	}
+	counters.PacketsSent = packetsSent.NewTotalPacketsSent
+	counters.PacketsReceived = packetsReceived.NewTotalPacketsReceived
+	counters.HasPackets = true
+	return &counters, nil
+}
+
+// This method returns the rates at which the counters increased since the
+// passed previous counters. A counter lower than previously is taken to have
+// wrapped around once, so that counters must be sampled at least once per
+// wraparound period; an IGD rebooting in between also yields wrong rates.
+// Packet rates are only computed if both counters have packet counters.
+func (self *TrafficCounters) RatesSince(previous *TrafficCounters) *TrafficRates {
+	interval := self.Time.Sub(previous.Time)
+	rate := func(current, previous uint32) float64 {
+		if interval <= 0 {
+			return 0
+		}
+		// Unsigned arithmetic takes care of the wraparound
+		return float64(current-previous) / interval.Seconds()
+	}
+	rates := TrafficRates{
+		Interval:               interval,
+		BytesSentPerSecond:     rate(self.BytesSent, previous.BytesSent),
+		BytesReceivedPerSecond: rate(self.BytesReceived, previous.BytesReceived),
+		HasPackets:             self.HasPackets && previous.HasPackets,
+	}
+	if rates.HasPackets {
+		rates.PacketsSentPerSecond = rate(self.PacketsSent, previous.PacketsSent)
+		rates.PacketsReceivedPerSecond = rate(self.PacketsReceived, previous.PacketsReceived)
+	}
+	return &rates
+}
+
+// This type turns successive traffic counters of an IGD into rates. Use
+// IGD.NewTrafficSampler() to obtain one.
+type TrafficSampler struct {
+	igd      *IGD
+	previous *TrafficCounters
+}
+
+// This method returns a TrafficSampler for the IGD.
+func (self *IGD) NewTrafficSampler() *TrafficSampler {
+	return &TrafficSampler{igd: self}
+}
+
+// This method fetches the traffic counters of the IGD and returns the rates
+// since the previous call, or nil rates upon the first call, which only
+// serves as a baseline. It must not be called concurrently.
+func (self *TrafficSampler) Sample(ctx context.Context) (*TrafficRates, error) {
+	counters, err := self.igd.GetTrafficCounters(ctx)
+	if err != nil {
+		return nil, err
+	}
+	previous := self.previous
+	self.previous = counters
+	if previous == nil {
+		return nil, nil
+	}
+	return counters.RatesSince(previous), nil
+}
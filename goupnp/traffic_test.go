@@ -0,0 +1,146 @@
+package goupnp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTrafficRatesWraparound(t *testing.T) {
+	start := time.Now()
+	previous := &TrafficCounters{
+		BytesSent:     1000,
+		BytesReceived: 0xFFFFFF00,
+		PacketsSent:   10,
+		HasPackets:    true,
+		Time:          start,
+	}
+	current := &TrafficCounters{
+		BytesSent:     3000,
+		BytesReceived: 0x00000300,
+		PacketsSent:   30,
+		HasPackets:    true,
+		Time:          start.Add(2 * time.Second),
+	}
+	rates := current.RatesSince(previous)
+	if rates.Interval != 2*time.Second || rates.BytesSentPerSecond != 1000 ||
+		rates.BytesReceivedPerSecond != 0x200 || rates.PacketsSentPerSecond != 10 ||
+		rates.PacketsReceivedPerSecond != 0 {
+		t.Errorf("Rates incorrectly computed as %+v", rates)
+	}
+
+	if rates := current.RatesSince(current); rates.BytesSentPerSecond != 0 {
+		t.Errorf("Rates over no time computed as %+v", rates)
+	}
+
+	// Packet counters missing from either sample are not mistaken for a
+	// wraparound
+	previous.HasPackets, previous.PacketsSent = false, 0
+	if rates := current.RatesSince(previous); rates.HasPackets || rates.PacketsSentPerSecond != 0 {
+		t.Errorf("Rates without previous packet counters computed as %+v", rates)
+	}
+	if rates := previous.RatesSince(current); rates.HasPackets || rates.PacketsSentPerSecond != 0 {
+		t.Errorf("Rates without current packet counters computed as %+v", rates)
+	}
+}
+
+func TestTrafficSamplerIntermittentPackets(t *testing.T) {
+	var packetsSent uint32 = 1000
+	packetsFail := false
+	igd := newSOAPTestIGD(t, func(action string, args map[string]string) map[string]string {
+		switch action {
+		case "GetTotalBytesSent":
+			return map[string]string{"NewTotalBytesSent": "0"}
+		case "GetTotalBytesReceived":
+			return map[string]string{"NewTotalBytesReceived": "0"}
+		case "GetTotalPacketsSent":
+			if packetsFail {
+				return nil
+			}
+			packetsSent += 10
+			return map[string]string{"NewTotalPacketsSent": fmt.Sprint(packetsSent)}
+		case "GetTotalPacketsReceived":
+			return map[string]string{"NewTotalPacketsReceived": "5"}
+		}
+		return nil
+	})
+	ctx := context.Background()
+
+	sampler := igd.NewTrafficSampler()
+	sampler.Sample(ctx)
+	// The packet counters fail then succeed again
+	for _, fail := range []bool{true, false} {
+		packetsFail = fail
+		rates, err := sampler.Sample(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rates.HasPackets || rates.PacketsSentPerSecond != 0 || rates.PacketsReceivedPerSecond != 0 {
+			t.Errorf("Rates incorrectly computed as %+v", rates)
+		}
+	}
+	rates, err := sampler.Sample(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !rates.HasPackets || rates.PacketsSentPerSecond != 10/rates.Interval.Seconds() {
+		t.Errorf("Rates incorrectly computed as %+v", rates)
+	}
+}
+
+func TestTrafficSampler(t *testing.T) {
+	bytesSent := 0xFFFFFFF0
+	igd := newSOAPTestIGD(t, func(action string, args map[string]string) map[string]string {
+		switch action {
+		case "GetCommonLinkProperties":
+			return map[string]string{
+				"NewWANAccessType":              "DSL",
+				"NewLayer1UpstreamMaxBitRate":   "1024000",
+				"NewLayer1DownstreamMaxBitRate": "16384000",
+				"NewPhysicalLinkStatus":         "Up",
+			}
+		case "GetTotalBytesSent":
+			sent := uint32(bytesSent)
+			bytesSent += 0x100
+			return map[string]string{"NewTotalBytesSent": fmt.Sprint(sent)}
+		case "GetTotalBytesReceived":
+			return map[string]string{"NewTotalBytesReceived": "42"}
+		}
+		// Packet counters are not implemented
+		return nil
+	})
+
+	properties, err := igd.GetCommonLinkProperties(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *properties != (LinkProperties{"DSL", 1024000, 16384000, "Up"}) {
+		t.Errorf("Link properties incorrectly parsed as %+v", properties)
+	}
+
+	sampler := igd.NewTrafficSampler()
+	if rates, err := sampler.Sample(context.Background()); rates != nil || err != nil {
+		t.Errorf("First sample returned %v, %v", rates, err)
+	}
+	rates, err := sampler.Sample(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The counter wrapped from 0xFFFFFFF0 to 0xF0
+	expected := 0x100 / rates.Interval.Seconds()
+	if rates.BytesSentPerSecond != expected || rates.BytesReceivedPerSecond != 0 ||
+		rates.PacketsSentPerSecond != 0 {
+		t.Errorf("Rates incorrectly computed as %+v", rates)
+	}
+}
+
+func TestNoCommonInterfaceConfig(t *testing.T) {
+	description := strings.Replace(belkinDescription, WANCommonInterfaceConfig1ServiceType,
+		"urn:example-com:service:Other:1", 1)
+	igd := newTestIGDWith(t, loopbackInterface, description, nil, nil)
+	if _, err := igd.GetTrafficCounters(context.Background()); err != ErrNoCommonInterfaceConfig {
+		t.Errorf("Expected ErrNoCommonInterfaceConfig, got %v", err)
+	}
+}
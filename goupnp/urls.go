@@ -0,0 +1,63 @@
+package goupnp
+
+import (
+	"net"
+	"net/url"
+	"strings"
+)
+
+// This function parses a URL which may contain an IPv6 literal host, such as
+// a LOCATION header or a control URL, and attaches the zone of the passed
+// interface to link-local hosts so that they may be fetched.
+//
+// Devices frequently include their own zone unescaped, e.g.
+// "http://[fe80::1%eth0]:5000/", which url.Parse rejects. Such zones name an
+// interface on the device rather than on the localhost and are therefore
+// replaced.
+func parseURLInZone(raw string, iface *net.Interface) (*url.URL, error) {
+	u, err := url.Parse(escapeZone(raw))
+	if err != nil {
+		return nil, err
+	}
+	setZone(u, iface)
+	return u, nil
+}
+
+// This function escapes a bare '%' introducing the zone of a bracketed IPv6
+// literal host as "%25", as mandated by RFC 6874.
+func escapeZone(raw string) string {
+	open := strings.Index(raw, "[")
+	closing := strings.Index(raw, "]")
+	if open < 0 || closing < open {
+		return raw
+	}
+	host := raw[open:closing]
+	percent := strings.Index(host, "%")
+	if percent < 0 || strings.HasPrefix(host[percent:], "%25") {
+		return raw
+	}
+	return raw[:open+percent] + "%25" + raw[open+percent+1:]
+}
+
+// This function replaces the zone of u's host with the name of the passed
+// interface if and only if it is a link-local IPv6 address, other hosts are
+// left as is.
+func setZone(u *url.URL, iface *net.Interface) {
+	ip := hostIP(u)
+	if iface == nil || ip == nil || ip.To4() != nil || !ip.IsLinkLocalUnicast() {
+		return
+	}
+	host := ip.String() + "%" + iface.Name
+	if port := u.Port(); port != "" {
+		u.Host = net.JoinHostPort(host, port)
+	} else {
+		u.Host = "[" + host + "]"
+	}
+}
+
+// This function returns the IP address u's host consists of, stripped of any
+// zone, or nil if the host is a name.
+func hostIP(u *url.URL) net.IP {
+	host, _, _ := strings.Cut(u.Hostname(), "%")
+	return net.ParseIP(host)
+}
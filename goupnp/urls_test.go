@@ -0,0 +1,28 @@
+package goupnp
+
+import (
+	"net"
+	"testing"
+)
+
+func TestParseURLInZone(t *testing.T) {
+	iface := &net.Interface{Index: 2, Name: "eth0"}
+	tests := []struct {
+		raw, expected string
+	}{
+		{"http://192.168.1.1:5000/rootDesc.xml", "http://192.168.1.1:5000/rootDesc.xml"},
+		{"http://[fe80::1]:5000/rootDesc.xml", "http://[fe80::1%25eth0]:5000/rootDesc.xml"},
+		{"http://[fe80::1%br-lan]:5000/rootDesc.xml", "http://[fe80::1%25eth0]:5000/rootDesc.xml"},
+		{"http://[fe80::1%25br-lan]/rootDesc.xml", "http://[fe80::1%25eth0]/rootDesc.xml"},
+		{"http://[2001:db8::1]:5000/rootDesc.xml", "http://[2001:db8::1]:5000/rootDesc.xml"},
+	}
+
+	for _, test := range tests {
+		u, err := parseURLInZone(test.raw, iface)
+		if err != nil {
+			t.Errorf("Failed to parse %v: %v", test.raw, err)
+		} else if u.String() != test.expected {
+			t.Errorf("%v parsed as %v, expected %v", test.raw, u, test.expected)
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package goupnp
+
+import (
+	"context"
+	"net"
+	"strings"
+	"time"
+
+	"log/slog"
+)
+
+// This type allows callers to tune WatchExternalIPWithOptions(). The zero
+// value, as well as a nil pointer, yields the defaults.
+type WatchOptions struct {
+	// How often the IGD is polled when it does not send events, 5 minutes by
+	// default. Subscribing to events is attempted again at each poll.
+	PollInterval time.Duration
+	// How long a new status must hold before being reported, 5 seconds by
+	// default, so that a connection flapping back to its previous status goes
+	// unreported
+	Debounce time.Duration
+}
+
+// How long each query of the status of the IGD may take, should it stop
+// answering.
+const watchQueryTimeout = 10 * time.Second
+
+func (self *WatchOptions) pollInterval() time.Duration {
+	if self == nil || self.PollInterval <= 0 {
+		return 5 * time.Minute
+	}
+	return self.PollInterval
+}
+
+func (self *WatchOptions) debounce() time.Duration {
+	if self == nil || self.Debounce <= 0 {
+		return 5 * time.Second
+	}
+	return self.Debounce
+}
+
+// This method returns a channel which is sent the ConnectionStatus of the IGD
+// whenever its external IP address or connection state changes, starting with
+// the current one, until ctx is done, whereupon it is closed. Should the
+// current status not be available at first, the first one learnt of later,
+// from an event or a poll, is sent as soon as it is.
+//
+// Changes are learnt of through events when the IGD supports them, and by
+// polling otherwise.
+func (self *IGD) WatchExternalIP(ctx context.Context) chan *ConnectionStatus {
+	return self.WatchExternalIPWithOptions(ctx, nil)
+}
+
+// This method behaves as WatchExternalIP() with the passed options. A nil opts
+// is equivalent to the zero WatchOptions.
+func (self *IGD) WatchExternalIPWithOptions(ctx context.Context,
+	opts *WatchOptions) (ret chan *ConnectionStatus) {
+	ret = make(chan *ConnectionStatus)
+
+	go func() {
+		defer close(ret)
+
+		var (
+			// The last status reported, and the one waiting to be
+			// reported should it hold
+			reported, pending *ConnectionStatus
+			debounce          = time.NewTimer(0)
+			// The state variables as last evented
+			connectionStatus, externalIP string
+		)
+		debounce.Stop()
+		defer debounce.Stop()
+
+		report := func(status *ConnectionStatus) bool {
+			select {
+			case ret <- status:
+				reported = status
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+		query := func() (*ConnectionStatus, bool) {
+			ctx, cancel := context.WithTimeout(ctx, watchQueryTimeout)
+			defer cancel()
+			return self.connectionStatus(ctx)
+		}
+		// This function returns false if ctx is done
+		observe := func(status *ConnectionStatus) bool {
+			if reported == nil {
+				// There is nothing to debounce against yet
+				pending = status
+				return report(status)
+			}
+			if pending == nil || !status.equal(pending) {
+				pending = status
+				debounce.Reset(opts.debounce())
+			}
+			return true
+		}
+
+		// The current status is reported straight away
+		if status, ok := query(); ok {
+			pending = status
+			if !report(status) {
+				return
+			}
+			connectionStatus = status.State.String()
+			if status.Connected {
+				externalIP = status.IP.String()
+			}
+		}
+
+		var subscription *Subscription
+		subscribe := func() {
+			var err error
+			subscription, err = self.Subscribe(ctx, self.ConnectionService())
+			if err != nil {
+				slog.Debug("Polling for external IP changes", "error", err)
+			}
+		}
+		defer func() {
+			if subscription != nil {
+				subscription.Close()
+			}
+		}()
+		subscribe()
+
+		poll := time.NewTicker(opts.pollInterval())
+		defer poll.Stop()
+		for {
+			var events chan *Event
+			if subscription != nil {
+				events = subscription.Events
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+
+			case event, ok := <-events:
+				if !ok {
+					subscription = nil
+					continue
+				}
+				value, statusChanged := event.Variables["ConnectionStatus"]
+				if statusChanged {
+					connectionStatus = value
+				}
+				value, ipChanged := event.Variables["ExternalIPAddress"]
+				if ipChanged {
+					externalIP = value
+				}
+				if (statusChanged || ipChanged) &&
+					!observe(newConnectionStatus(connectionStatus, externalIP)) {
+					return
+				}
+
+			case <-poll.C:
+				if subscription == nil {
+					subscribe()
+				}
+				if subscription == nil {
+					if status, ok := query(); ok && !observe(status) {
+						return
+					}
+				}
+
+			case <-debounce.C:
+				if reported == nil || !pending.equal(reported) {
+					if !report(pending) {
+						return
+					}
+				}
+			}
+		}
+	}()
+
+	return
+}
+
+// This function returns the ConnectionStatus described by the passed values of
+// the ConnectionStatus and ExternalIPAddress state variables. Its Uptime and
+// LastError are unknown as neither is evented.
+func newConnectionStatus(connectionStatus, externalIP string) *ConnectionStatus {
+	status := ConnectionStatus{State: ParseConnectionState(connectionStatus)}
+	status.Connected = status.State == ConnectionConnected
+	if status.Connected {
+		status.IP = net.ParseIP(strings.TrimSpace(externalIP))
+	}
+	return &status
+}
+
+// This method returns true if and only if both statuses have the same state
+// and IP address, other fields changing as a matter of course.
+func (self *ConnectionStatus) equal(other *ConnectionStatus) bool {
+	return self.State == other.State && self.IP.Equal(other.IP)
+}
@@ -0,0 +1,165 @@
+package goupnp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const externalIPResponseString = `<?xml version="1.0"?>
+<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/" ` +
+	`s:encodingStyle="http://schemas.xmlsoap.org/soap/encoding/"><s:Body>` +
+	`<u:GetExternalIPAddressResponse xmlns:u="urn:schemas-upnp-org:service:WANIPConnection:1">` +
+	`<NewExternalIPAddress>%s</NewExternalIPAddress>` +
+	`</u:GetExternalIPAddressResponse></s:Body></s:Envelope>
+`
+
+// This type plays the connection service of an IGD whose status and external
+// IP address tests may change at will. Events are only supported if
+// callbacks is non-nil. The first failures SOAP requests fail.
+type testConnection struct {
+	mutex     sync.Mutex
+	status    string
+	ip        string
+	callbacks chan string
+	failures  int
+}
+
+func (self *testConnection) set(status, ip string) {
+	self.mutex.Lock()
+	self.status, self.ip = status, ip
+	self.mutex.Unlock()
+}
+
+func (self *testConnection) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	switch {
+	case r.Method == "SUBSCRIBE" && self.callbacks != nil:
+		if callback := r.Header.Get("CALLBACK"); callback != "" {
+			self.callbacks <- strings.Trim(callback, "<>")
+		}
+		w.Header().Set("SID", "uuid:watch")
+		w.Header().Set("TIMEOUT", "Second-1800")
+	case r.Method == "UNSUBSCRIBE" && self.callbacks != nil:
+	case r.Method == "POST" && self.failures > 0:
+		self.failures--
+		w.WriteHeader(http.StatusInternalServerError)
+	case strings.HasSuffix(r.Header.Get("SOAPAction"), `#GetStatusInfo"`):
+		fmt.Fprintf(w, statusResponseString, connectionTypeStringWANIP, self.status)
+	case strings.HasSuffix(r.Header.Get("SOAPAction"), `#GetExternalIPAddress"`):
+		fmt.Fprintf(w, externalIPResponseString, self.ip)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func expectStatus(t *testing.T, statuses chan *ConnectionStatus, connected bool, ip string) {
+	t.Helper()
+	select {
+	case status := <-statuses:
+		if status == nil || status.Connected != connected ||
+			(connected && status.IP.String() != ip) {
+			t.Errorf("Received %v, expected %v %v", status, connected, ip)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Timed out waiting for %v %v", connected, ip)
+	}
+}
+
+func TestWatchExternalIPPolling(t *testing.T) {
+	connection := &testConnection{status: "Connected", ip: "203.0.113.7"}
+	igd := newTestIGDWith(t, loopbackInterface, belkinDescription, nil, connection)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	statuses := igd.WatchExternalIPWithOptions(ctx, &WatchOptions{
+		PollInterval: 10 * time.Millisecond,
+		Debounce:     30 * time.Millisecond,
+	})
+	expectStatus(t, statuses, true, "203.0.113.7")
+
+	connection.set("Connected", "203.0.113.8")
+	expectStatus(t, statuses, true, "203.0.113.8")
+	connection.set("Disconnected", "")
+	expectStatus(t, statuses, false, "")
+
+	cancel()
+	for range statuses {
+	}
+}
+
+func TestWatchExternalIPInitialFailure(t *testing.T) {
+	connection := &testConnection{status: "Connected", ip: "203.0.113.7", failures: 1}
+	igd := newTestIGDWith(t, loopbackInterface, belkinDescription, nil, connection)
+
+	// The first status successfully polled is reported without waiting for
+	// the debounce delay
+	ctx, cancel := context.WithCancel(context.Background())
+	statuses := igd.WatchExternalIPWithOptions(ctx, &WatchOptions{
+		PollInterval: 10 * time.Millisecond,
+		Debounce:     time.Hour,
+	})
+	expectStatus(t, statuses, true, "203.0.113.7")
+
+	cancel()
+	for range statuses {
+	}
+}
+
+func TestWatchExternalIPStalledIGD(t *testing.T) {
+	// The IGD accepts connections but never answers
+	stop := make(chan struct{})
+	defer close(stop)
+	igd := newTestIGDWith(t, loopbackInterface, belkinDescription, nil,
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-stop
+		}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	statuses := igd.WatchExternalIP(ctx)
+	time.Sleep(100 * time.Millisecond)
+	cancel()
+	select {
+	case status, ok := <-statuses:
+		if ok {
+			t.Errorf("Received %v from a stalled IGD", status)
+		}
+	case <-time.After(time.Second):
+		t.Error("Watch not stopped by cancelling its context")
+	}
+}
+
+func TestWatchExternalIPEvents(t *testing.T) {
+	connection := &testConnection{status: "Connected", ip: "203.0.113.7",
+		callbacks: make(chan string, 1)}
+	igd := newTestIGDWith(t, loopbackInterface, belkinDescription, nil, connection)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	statuses := igd.WatchExternalIPWithOptions(ctx, &WatchOptions{
+		PollInterval: time.Hour,
+		Debounce:     200 * time.Millisecond,
+	})
+	expectStatus(t, statuses, true, "203.0.113.7")
+	callback := <-connection.callbacks
+
+	const propertySet = `<e:propertyset xmlns:e="urn:schemas-upnp-org:event-1-0">` +
+		`<e:property><%[1]s>%[2]s</%[1]s></e:property></e:propertyset>`
+	// The connection flaps, which goes unreported
+	notify(t, callback, "uuid:watch", 0, fmt.Sprintf(propertySet, "ConnectionStatus", "Disconnected"))
+	notify(t, callback, "uuid:watch", 1, fmt.Sprintf(propertySet, "ConnectionStatus", "Connected"))
+	notify(t, callback, "uuid:watch", 2, fmt.Sprintf(propertySet, "ExternalIPAddress", "203.0.113.8"))
+	expectStatus(t, statuses, true, "203.0.113.8")
+	select {
+	case status := <-statuses:
+		t.Errorf("Unexpected %v", status)
+	case <-time.After(300 * time.Millisecond):
+	}
+
+	cancel()
+	for range statuses {
+	}
+}
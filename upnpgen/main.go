@@ -0,0 +1,282 @@
+// This command generates typed Go clients for UPnP services from their
+// service description (SCPD) documents. It is meant to be run by go generate
+// from within the goupnp package, the clients it emits rely on the unexported
+// helpers of that package.
+//
+// Usage:
+//
+//	upnpgen -scpd scpd/WANIPConnection1.xml \
+//	    -type urn:schemas-upnp-org:service:WANIPConnection:1 \
+//	    -name WANIPConnection1 -o gen_wanipconnection1.go
+//
+// For each action of the service a method is generated on the client taking a
+// request struct of its in-arguments and returning a response struct of its
+// out-arguments, whose fields have the Go type corresponding to the data type
+// of the related state variable.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/nhelke/goupnpc/goupnp"
+)
+
+// This type describes how values of a UPnP data type are represented in Go
+// and converted from and to the strings sent over SOAP. Format and Parse are
+// format strings whose sole verb is replaced by the expression to convert.
+type goType struct {
+	Name   string
+	Format string
+	Parse  string
+}
+
+// The UPnP data types are those of section 2.5 of the UPnP Device
+// Architecture, any type not listed here is handled as a string.
+var goTypes = map[string]goType{
+	"ui1":         {"uint8", "formatUint(uint64(%s))", "parseUint[uint8](%s, 8)"},
+	"ui2":         {"uint16", "formatUint(uint64(%s))", "parseUint[uint16](%s, 16)"},
+	"ui4":         {"uint32", "formatUint(uint64(%s))", "parseUint[uint32](%s, 32)"},
+	"ui8":         {"uint64", "formatUint(%s)", "parseUint[uint64](%s, 64)"},
+	"i1":          {"int8", "formatInt(int64(%s))", "parseInt[int8](%s, 8)"},
+	"i2":          {"int16", "formatInt(int64(%s))", "parseInt[int16](%s, 16)"},
+	"i4":          {"int32", "formatInt(int64(%s))", "parseInt[int32](%s, 32)"},
+	"int":         {"int32", "formatInt(int64(%s))", "parseInt[int32](%s, 32)"},
+	"i8":          {"int64", "formatInt(%s)", "parseInt[int64](%s, 64)"},
+	"r4":          {"float32", "formatFloat(float64(%s))", "parseFloat[float32](%s, 32)"},
+	"r8":          {"float64", "formatFloat(%s)", "parseFloat[float64](%s, 64)"},
+	"number":      {"float64", "formatFloat(%s)", "parseFloat[float64](%s, 64)"},
+	"float":       {"float64", "formatFloat(%s)", "parseFloat[float64](%s, 64)"},
+	"boolean":     {"bool", "formatBool(%s)", "parseBool(%s)"},
+	"date":        {"time.Time", "formatDateTime(%s)", "parseDateTime(%s)"},
+	"dateTime":    {"time.Time", "formatDateTime(%s)", "parseDateTime(%s)"},
+	"dateTime.tz": {"time.Time", "formatDateTime(%s)", "parseDateTime(%s)"},
+	"time":        {"time.Time", "formatDateTime(%s)", "parseDateTime(%s)"},
+	"time.tz":     {"time.Time", "formatDateTime(%s)", "parseDateTime(%s)"},
+}
+
+var stringType = goType{"string", "%s", ""}
+
+type field struct {
+	Name     string // the name of the argument
+	GoName   string
+	Type     goType
+	Variable string
+}
+
+func (self field) FormatExpr(receiver string) string {
+	return fmt.Sprintf(self.Type.Format, receiver+"."+self.GoName)
+}
+
+func (self field) ParseExpr(value string) string {
+	return fmt.Sprintf(self.Type.Parse, value)
+}
+
+type action struct {
+	Name   string
+	GoName string
+	In     []field
+	Out    []field
+}
+
+type service struct {
+	Source   string
+	Package  string
+	Type     string
+	Name     string
+	Actions  []action
+	UsesTime bool
+}
+
+// This function turns the passed name into an exported Go identifier,
+// replacing any character which may not appear in one by an underscore.
+func goName(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		switch {
+		case unicode.IsLetter(r) || r == '_':
+		case unicode.IsDigit(r) && i > 0:
+		default:
+			r = '_'
+		}
+		if i == 0 {
+			r = unicode.ToUpper(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func fieldsOf(scpd *goupnp.SCPD, arguments []goupnp.Argument, usesTime *bool) (ret []field) {
+	for _, argument := range arguments {
+		t := stringType
+		if variable := scpd.StateVariable(argument.RelatedStateVariable); variable != nil {
+			if known, ok := goTypes[variable.DataType]; ok {
+				t = known
+			}
+		}
+		if t.Name == "time.Time" {
+			*usesTime = true
+		}
+		ret = append(ret, field{
+			Name:     argument.Name,
+			GoName:   goName(argument.Name),
+			Type:     t,
+			Variable: argument.RelatedStateVariable,
+		})
+	}
+	return
+}
+
+// This function returns the Go source of a client for the passed service.
+func generate(scpd *goupnp.SCPD, svc service) ([]byte, error) {
+	for _, a := range scpd.Actions {
+		svc.Actions = append(svc.Actions, action{
+			Name:   a.Name,
+			GoName: goName(a.Name),
+			In:     fieldsOf(scpd, a.InArguments(), &svc.UsesTime),
+			Out:    fieldsOf(scpd, a.OutArguments(), &svc.UsesTime),
+		})
+	}
+
+	var buf bytes.Buffer
+	if err := clientTemplate.Execute(&buf, svc); err != nil {
+		return nil, err
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("Generated invalid code: %w\n%s", err, buf.Bytes())
+	}
+	return src, nil
+}
+
+var clientTemplate = template.Must(template.New("client").Parse(`// Code generated by upnpgen from {{.Source}}. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"context"
+	"fmt"
+{{- if .UsesTime}}
+	"time"
+{{- end}}
+)
+
+// The type of the services {{.Name}} is a client for.
+const {{.Name}}ServiceType = "{{.Type}}"
+
+// This type is a client for a service of type {{.Name}}ServiceType of an
+// IGD. Use IGD.{{.Name}}Clients() to obtain one.
+type {{.Name}} struct {
+	igd     *IGD
+	Service *Service
+}
+
+// This method returns a client for every service of type
+// {{.Name}}ServiceType of the IGD, in the order they appear in its
+// description.
+func (self *IGD) {{.Name}}Clients() (ret []*{{.Name}}) {
+	for _, service := range self.description.Device.FindServices({{.Name}}ServiceType) {
+		ret = append(ret, &{{.Name}}{igd: self, Service: service})
+	}
+	return
+}
+{{range $action := .Actions}}
+{{- if .In}}
+// The in-arguments of the {{.Name}} action.
+type {{$.Name}}{{.GoName}}Request struct {
+{{- range .In}}
+	{{.GoName}} {{.Type.Name}} // {{.Variable}}
+{{- end}}
+}
+{{end}}
+{{- if .Out}}
+// The out-arguments of the {{.Name}} action.
+type {{$.Name}}{{.GoName}}Response struct {
+{{- range .Out}}
+	{{.GoName}} {{.Type.Name}} // {{.Variable}}
+{{- end}}
+}
+{{end}}
+// This method invokes the {{.Name}} action.
+//
+// Failures reported by the IGD are returned as *UPnPError.
+func (self *{{$.Name}}) {{.GoName}}(ctx context.Context{{if .In}}, request *{{$.Name}}{{.GoName}}Request{{end}}) ({{if .Out}}*{{$.Name}}{{.GoName}}Response, {{end}}error) {
+	{{if .Out}}out{{else}}_{{end}}, err := self.igd.Invoke(ctx, self.Service, "{{.Name}}", {{if .In}}map[string]string{
+{{- range .In}}
+		"{{.Name}}": {{.FormatExpr "request"}},
+{{- end}}
+	}{{else}}nil{{end}})
+	if err != nil {
+		return {{if .Out}}nil, {{end}}fmt.Errorf("{{.Name}}: %w", err)
+	}
+{{- if .Out}}
+	var response {{$.Name}}{{.GoName}}Response
+{{- range .Out}}
+{{- if .Type.Parse}}
+	if value, ok := out["{{.Name}}"]; ok {
+		if response.{{.GoName}}, err = {{.ParseExpr "value"}}; err != nil {
+			return nil, fmt.Errorf("{{$action.Name}}: {{.Name}}: %w", err)
+		}
+	}
+{{- else}}
+	response.{{.GoName}} = out["{{.Name}}"]
+{{- end}}
+{{- end}}
+	return &response, nil
+{{- else}}
+	return nil
+{{- end}}
+}
+{{end}}`))
+
+func main() {
+	var (
+		scpdPath    = flag.String("scpd", "", "path of the SCPD document of the service")
+		serviceType = flag.String("type", "", "service type, e.g. urn:schemas-upnp-org:service:WANIPConnection:1")
+		name        = flag.String("name", "", "name of the generated client type")
+		output      = flag.String("o", "", "path of the generated file, standard output if empty")
+		pkg         = flag.String("package", "goupnp", "package of the generated file")
+	)
+	flag.Parse()
+	if *scpdPath == "" || *serviceType == "" || *name == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	body, err := os.ReadFile(*scpdPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	scpd, err := goupnp.ParseSCPD(body)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, *scpdPath+":", err)
+		os.Exit(1)
+	}
+
+	src, err := generate(scpd, service{
+		Source:  filepath.ToSlash(*scpdPath),
+		Package: *pkg,
+		Type:    *serviceType,
+		Name:    *name,
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	if *output == "" {
+		os.Stdout.Write(src)
+	} else if err = os.WriteFile(*output, src, 0644); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
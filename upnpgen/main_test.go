@@ -0,0 +1,120 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/nhelke/goupnpc/goupnp"
+)
+
+func TestGoName(t *testing.T) {
+	names := map[string]string{
+		"NewExternalPort":     "NewExternalPort",
+		"X_AVM-DE_GetNumbers": "X_AVM_DE_GetNumbers",
+		"lowerCase":           "LowerCase",
+	}
+	for name, expected := range names {
+		if actual := goName(name); actual != expected {
+			t.Errorf("goName(%q) = %q, expected %q", name, actual, expected)
+		}
+	}
+}
+
+func TestGenerateTypes(t *testing.T) {
+	const document = `<?xml version="1.0"?>
+<scpd xmlns="urn:schemas-upnp-org:service-1-0">
+	<actionList>
+		<action>
+			<name>X_SetSchedule</name>
+			<argumentList>
+				<argument><name>Start</name><direction>in</direction><relatedStateVariable>A_ARG_Time</relatedStateVariable></argument>
+				<argument><name>Enabled</name><direction>in</direction><relatedStateVariable>A_ARG_Bool</relatedStateVariable></argument>
+				<argument><name>Slot</name><direction>out</direction><relatedStateVariable>A_ARG_Slot</relatedStateVariable></argument>
+			</argumentList>
+		</action>
+	</actionList>
+	<serviceStateTable>
+		<stateVariable sendEvents="no"><name>A_ARG_Time</name><dataType>dateTime</dataType></stateVariable>
+		<stateVariable sendEvents="no"><name>A_ARG_Bool</name><dataType>boolean</dataType></stateVariable>
+		<stateVariable sendEvents="no"><name>A_ARG_Slot</name><dataType>i4</dataType></stateVariable>
+	</serviceStateTable>
+</scpd>
+`
+	scpd, err := goupnp.ParseSCPD([]byte(document))
+	if err != nil {
+		t.Fatal(err)
+	}
+	src, err := generate(scpd, service{
+		Source:  "test.xml",
+		Package: "goupnp",
+		Type:    "urn:example-com:service:Scheduler:1",
+		Name:    "Scheduler1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		`"time"`,
+		"Start   time.Time",
+		"Enabled bool",
+		"Slot int32",
+		`"Start":   formatDateTime(request.Start)`,
+		"parseInt[int32](value, 32)",
+		"func (self *Scheduler1) X_SetSchedule(ctx context.Context, request *Scheduler1X_SetScheduleRequest) (*Scheduler1X_SetScheduleResponse, error)",
+	} {
+		if !strings.Contains(string(src), expected) {
+			t.Errorf("Generated code does not contain %q:\n%s", expected, src)
+		}
+	}
+}
+
+// The generated clients of package goupnp must be kept in sync with the
+// SCPDs and this generator, run go generate ./... should this test fail.
+func TestGeneratedClientsUpToDate(t *testing.T) {
+	clients := []struct{ scpd, serviceType, name, output string }{
+		{"WANIPConnection1.xml", "urn:schemas-upnp-org:service:WANIPConnection:1",
+			"WANIPConnection1", "gen_wanipconnection1.go"},
+		{"WANPPPConnection1.xml", "urn:schemas-upnp-org:service:WANPPPConnection:1",
+			"WANPPPConnection1", "gen_wanpppconnection1.go"},
+		{"WANCommonInterfaceConfig1.xml", "urn:schemas-upnp-org:service:WANCommonInterfaceConfig:1",
+			"WANCommonInterfaceConfig1", "gen_wancommoninterfaceconfig1.go"},
+		{"Layer3Forwarding1.xml", "urn:schemas-upnp-org:service:Layer3Forwarding:1",
+			"Layer3Forwarding1", "gen_layer3forwarding1.go"},
+		{"LANHostConfigManagement1.xml", "urn:schemas-upnp-org:service:LANHostConfigManagement:1",
+			"LANHostConfigManagement1", "gen_lanhostconfigmanagement1.go"},
+		{"WANDSLLinkConfig1.xml", "urn:schemas-upnp-org:service:WANDSLLinkConfig:1",
+			"WANDSLLinkConfig1", "gen_wandsllinkconfig1.go"},
+		{"WANEthernetLinkConfig1.xml", "urn:schemas-upnp-org:service:WANEthernetLinkConfig:1",
+			"WANEthernetLinkConfig1", "gen_wanethernetlinkconfig1.go"},
+		{"WANCableLinkConfig1.xml", "urn:schemas-upnp-org:service:WANCableLinkConfig:1",
+			"WANCableLinkConfig1", "gen_wancablelinkconfig1.go"},
+	}
+	for _, client := range clients {
+		body, err := os.ReadFile("../goupnp/scpd/" + client.scpd)
+		if err != nil {
+			t.Fatal(err)
+		}
+		scpd, err := goupnp.ParseSCPD(body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		src, err := generate(scpd, service{
+			Source:  "scpd/" + client.scpd,
+			Package: "goupnp",
+			Type:    client.serviceType,
+			Name:    client.name,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		existing, err := os.ReadFile("../goupnp/" + client.output)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(src, existing) {
+			t.Errorf("%s is out of date", client.output)
+		}
+	}
+}